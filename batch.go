@@ -0,0 +1,34 @@
+package pathrouter
+
+import "errors"
+
+// Route 是 AddAll 添加的路由，Name 不为空时同时关联名称
+type Route[T any] struct {
+	Name    string
+	Pattern string
+	Value   T
+}
+
+// AddAll 在 Router 的副本上依次添加 routes，全部成功时才应用修改，
+// 否则 Router 保持不变，返回以 errors.Join 合并的全部错误
+// 失败的路由不会被添加，之后的路由继续检查
+func (r *Router[T]) AddAll(routes []Route[T]) error {
+	cp := *r
+	var errs []error
+	for _, route := range routes {
+		var err error
+		if route.Name != "" {
+			err = cp.AddNamed(route.Name, route.Pattern, route.Value)
+		} else {
+			err = cp.Add(route.Pattern, route.Value)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	*r = cp
+	return nil
+}
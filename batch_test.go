@@ -0,0 +1,56 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouter_AddAll(t *testing.T) {
+	r := &Router[int]{Duplicate: DuplicateError}
+	r.Add("/a", 1)
+	err := r.AddAll([]Route[int]{
+		{Name: "user", Pattern: "/users/:id", Value: 2},
+		{Pattern: "/static/*filepath", Value: 3},
+	})
+	if err != nil {
+		t.Fatalf("Router.AddAll() error = %v", err)
+	}
+	var res MatchResult[int]
+	if !r.Match("/users/1", &res) || res.Value != 2 {
+		t.Errorf("Router.Match() after AddAll got %+v", res)
+	}
+	if pattern, _ := r.Pattern("user"); pattern != "/users/:id" {
+		t.Errorf("Router.Pattern() got %q", pattern)
+	}
+
+	before := *r
+	err = r.AddAll([]Route[int]{
+		{Pattern: "/b", Value: 4},
+		{Pattern: "/users/:name/posts", Value: 5},
+		{Pattern: "/:", Value: 6},
+		{Pattern: "/a", Value: 7},
+		{Name: "user", Pattern: "/c", Value: 8},
+		{Pattern: "/d", Value: 9},
+	})
+	for _, want := range []error{ErrConflict, ErrInvalidPath, ErrDuplicate} {
+		if !errors.Is(err, want) {
+			t.Errorf("Router.AddAll() error = %v, want %v", err, want)
+		}
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 4 {
+		t.Errorf("Router.AddAll() returned %d errors, want 4", n)
+	}
+	if !reflect.DeepEqual(*r, before) {
+		t.Errorf("Router.AddAll() modified router on failure")
+	}
+	if r.Match("/b", &res) || r.Match("/d", &res) {
+		t.Errorf("Router.Match() matched route from failed AddAll")
+	}
+
+	// 同一批次中的重复路由同样按照 Duplicate 处理
+	err = r.AddAll([]Route[int]{{Pattern: "/e", Value: 10}, {Pattern: "/e", Value: 11}})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Router.AddAll() error = %v, want %v", err, ErrDuplicate)
+	}
+}
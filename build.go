@@ -0,0 +1,123 @@
+package pathrouter
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+)
+
+// AddNamed 添加路由并将 name 关联到 path，用于 Build
+func (r *Router[T]) AddNamed(name string, path string, value T) error {
+	err := r.checkName(name, path)
+	if err != nil {
+		return err
+	}
+	err = r.Add(path, value)
+	if err != nil {
+		return err
+	}
+	r.setName(name, path)
+	return nil
+}
+
+func (r *Router[T]) checkName(name string, path string) error {
+	if old, ok := r.names[name]; ok && old != path {
+		return fmt.Errorf("%w: route name %q is used by %q", ErrDuplicate, name, old)
+	}
+	return nil
+}
+
+func (r *Router[T]) setName(name string, path string) {
+	names := maps.Clone(r.names)
+	if names == nil {
+		names = make(map[string]string)
+	}
+	names[name] = path
+	r.names = names
+}
+
+// Pattern 返回 name 关联的 pattern
+func (r *Router[T]) Pattern(name string) (string, bool) {
+	pattern, ok := r.names[name]
+	return pattern, ok
+}
+
+func (r *Router[T]) removeNames(path string) {
+	var names map[string]string
+	for name, pattern := range r.names {
+		if pattern == path {
+			if names == nil {
+				names = maps.Clone(r.names)
+			}
+			delete(names, name)
+		}
+	}
+	if names != nil {
+		r.names = names
+	}
+}
+
+// Build 使用 ps 生成名称为 name 的路由的路径，规则与 URL 相同
+func (r *Router[T]) Build(name string, ps Params) (string, error) {
+	pattern, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("%w: route name %q", ErrNotFound, name)
+	}
+	return r.URL(pattern, ps)
+}
+
+// URL 使用 ps 替换 pattern 中的 param 和 *，返回转义后的路径
+// pattern 必须是已添加的路由，且未转义的路径必须能够以相同的 param 匹配到该路由
+// * 的值不能包含 . 和 .. 片段以及除末尾以外的空片段
+func (r *Router[T]) URL(pattern string, ps Params) (string, error) {
+	segs, err := parsePath[T](pattern)
+	if err != nil {
+		return "", err
+	}
+	stack := r.find(segs)
+	if stack == nil || !stack[len(stack)-1].end {
+		return "", fmt.Errorf("%w: %q", ErrNotFound, pattern)
+	}
+
+	var raw, escaped strings.Builder
+	for _, seg := range segs {
+		if seg.kind == staticKind {
+			raw.WriteString(seg.path)
+			escaped.WriteString(seg.path)
+			continue
+		}
+		value, ok := ps.Get(seg.key)
+		if !ok {
+			return "", fmt.Errorf("%w: missing param %q in %q", ErrBuild, seg.key, pattern)
+		}
+		raw.WriteString(value)
+		if seg.kind == paramKind {
+			escaped.WriteString(url.PathEscape(value))
+		} else {
+			// * 的值可以包含 /，逐段转义
+			parts := strings.Split(value, "/")
+			for i, part := range parts {
+				// . 和 .. 以及连续的 / 会被客户端和代理规范化，只允许空的值和末尾的 /
+				if part == "." || part == ".." || part == "" && i != len(parts)-1 {
+					return "", fmt.Errorf("%w: param %q of %q contains dot or empty segment", ErrBuild, seg.key, pattern)
+				}
+				if i > 0 {
+					escaped.WriteByte('/')
+				}
+				escaped.WriteString(url.PathEscape(part))
+			}
+		}
+	}
+
+	var res MatchResult[T]
+	if r.root.lookup(raw.String(), &res, nil) != stack[len(stack)-1] {
+		return "", fmt.Errorf("%w: %q does not match %q", ErrBuild, raw.String(), pattern)
+	}
+	for _, p := range res.Params {
+		if value, _ := ps.Get(p.Key); value != p.Value {
+			return "", fmt.Errorf("%w: param %q of %q does not round-trip", ErrBuild, p.Key, pattern)
+		}
+	}
+	return escaped.String(), nil
+}
@@ -0,0 +1,101 @@
+package pathrouter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRouter_URL(t *testing.T) {
+	r := buildRouter([]string{
+		"/users/:id",
+		"/users/new",
+		"/repos/:owner/:repo",
+		"/files/:name.:ext",
+		"/static/*filepath",
+		"/codes/:code{[0-9]{3}}",
+		"/n/:v<int>",
+		"/all/*",
+	})
+	tests := []struct {
+		name    string
+		pattern string
+		ps      Params
+		want    string
+		wantErr error
+	}{
+		{name: "param", pattern: "/users/:id", ps: Params{{Key: "id", Value: "42"}}, want: "/users/42"},
+		{name: "params", pattern: "/repos/:owner/:repo", ps: Params{{Key: "repo", Value: "pathrouter"}, {Key: "owner", Value: "vizee"}}, want: "/repos/vizee/pathrouter"},
+		{name: "escape", pattern: "/users/:id", ps: Params{{Key: "id", Value: "a b?"}}, want: "/users/a%20b%3F"},
+		{name: "multi", pattern: "/files/:name.:ext", ps: Params{{Key: "name", Value: "a"}, {Key: "ext", Value: "tar.gz"}}, want: "/files/a.tar.gz"},
+		{name: "trailing", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "css/a b.css"}}, want: "/static/css/a%20b.css"},
+		{name: "trailing-unnamed", pattern: "/all/*", ps: Params{{Key: "*", Value: ""}}, want: "/all/"},
+		{name: "trailing-slash", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "css/"}}, want: "/static/css/"},
+		{name: "trailing-dots", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "a..b/.c"}}, want: "/static/a..b/.c"},
+		{name: "static", pattern: "/users/new", want: "/users/new"},
+		{name: "regexp", pattern: "/codes/:code{[0-9]{3}}", ps: Params{{Key: "code", Value: "404"}}, want: "/codes/404"},
+		{name: "typed", pattern: "/n/:v<int>", ps: Params{{Key: "v", Value: "-1"}}, want: "/n/-1"},
+		{name: "missing", pattern: "/repos/:owner/:repo", ps: Params{{Key: "owner", Value: "vizee"}}, wantErr: ErrBuild},
+		{name: "slash", pattern: "/users/:id", ps: Params{{Key: "id", Value: "a/b"}}, wantErr: ErrBuild},
+		{name: "shadowed", pattern: "/users/:id", ps: Params{{Key: "id", Value: "new"}}, wantErr: ErrBuild},
+		{name: "empty", pattern: "/users/:id", ps: Params{{Key: "id", Value: ""}}, wantErr: ErrBuild},
+		{name: "ambiguous", pattern: "/files/:name.:ext", ps: Params{{Key: "name", Value: "a.b"}, {Key: "ext", Value: "c"}}, wantErr: ErrBuild},
+		{name: "regexp-fail", pattern: "/codes/:code{[0-9]{3}}", ps: Params{{Key: "code", Value: "4040"}}, wantErr: ErrBuild},
+		{name: "typed-fail", pattern: "/n/:v<int>", ps: Params{{Key: "v", Value: "x"}}, wantErr: ErrBuild},
+		{name: "trailing-dotdot", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "../../etc"}}, wantErr: ErrBuild},
+		{name: "trailing-dot", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "a/./b"}}, wantErr: ErrBuild},
+		{name: "trailing-dot-end", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "a/.."}}, wantErr: ErrBuild},
+		{name: "trailing-empty-segment", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "a//b"}}, wantErr: ErrBuild},
+		{name: "trailing-leading-slash", pattern: "/static/*filepath", ps: Params{{Key: "filepath", Value: "/etc"}}, wantErr: ErrBuild},
+		{name: "not-found", pattern: "/users/:name", ps: Params{{Key: "name", Value: "a"}}, wantErr: ErrNotFound},
+		{name: "invalid", pattern: "/users/:", wantErr: ErrInvalidPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.URL(tt.pattern, tt.ps)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Router.URL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Router.URL() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_Build(t *testing.T) {
+	r := &Router[int]{}
+	if err := r.AddNamed("user", "/users/:id", 100); err != nil {
+		t.Fatalf("Router.AddNamed() error = %v", err)
+	}
+	if err := r.AddNamed("repo", "/repos/:owner/:repo", 101); err != nil {
+		t.Fatalf("Router.AddNamed() error = %v", err)
+	}
+	if err := r.AddNamed("user", "/u/:id", 102); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("Router.AddNamed() error = %v, wantErr %v", err, ErrDuplicate)
+	}
+
+	got, err := r.Build("user", Params{{Key: "id", Value: "42"}})
+	if err != nil || got != "/users/42" {
+		t.Fatalf("Router.Build() got %q, %v", got, err)
+	}
+	var res MatchResult[int]
+	if !r.Match(got, &res) || res.Value != 100 {
+		t.Fatalf("Router.Match(%q) got %v, want %v", got, res.Value, 100)
+	}
+
+	if _, err := r.Build("unknown", nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Router.Build() error = %v, wantErr %v", err, ErrNotFound)
+	}
+
+	snapshot := *r
+	r.Remove("/users/:id")
+	if _, ok := r.Pattern("user"); ok {
+		t.Fatal("Router.Remove() kept the route name")
+	}
+	if pattern, ok := snapshot.Pattern("user"); !ok || pattern != "/users/:id" {
+		t.Fatal("Router.Remove() changed the snapshot names")
+	}
+	if _, ok := r.Pattern("repo"); !ok {
+		t.Fatal("Router.Remove() removed an unrelated route name")
+	}
+}
@@ -0,0 +1,29 @@
+package pathrouter
+
+import (
+	"path"
+	"strings"
+)
+
+// CleanPath 返回规范化的路径：以 / 开头，合并连续的 /，处理 . 和 ..，
+// 保留末尾的 /，以 . 或 .. 结尾的路径视为以 / 结尾
+// 已经规范的路径原样返回，不会分配内存
+func CleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	np := path.Clean(p)
+	if np == "/" {
+		return np
+	}
+	if strings.HasSuffix(p, "/") || strings.HasSuffix(p, "/.") || strings.HasSuffix(p, "/..") {
+		if len(p) == len(np)+1 && strings.HasPrefix(p, np) {
+			return p
+		}
+		return np + "/"
+	}
+	return np
+}
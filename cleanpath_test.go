@@ -0,0 +1,83 @@
+package pathrouter
+
+import (
+	"testing"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "", want: "/"},
+		{path: "/", want: "/"},
+		{path: "a", want: "/a"},
+		{path: "a/", want: "/a/"},
+		{path: "/a/b", want: "/a/b"},
+		{path: "/a/b/", want: "/a/b/"},
+		{path: "//", want: "/"},
+		{path: "/a//b", want: "/a/b"},
+		{path: "/a//b//", want: "/a/b/"},
+		{path: "/a/./b", want: "/a/b"},
+		{path: "/a/.", want: "/a/"},
+		{path: "/a/b/..", want: "/a/"},
+		{path: "/a/b/../", want: "/a/"},
+		{path: "/a/b/../c", want: "/a/c"},
+		{path: "/a/..", want: "/"},
+		{path: "/..", want: "/"},
+		{path: "/../a/../../b", want: "/b"},
+		{path: "/a/.../b", want: "/a/.../b"},
+		{path: "/a/..b/c", want: "/a/..b/c"},
+	}
+	for _, tt := range tests {
+		if got := CleanPath(tt.path); got != tt.want {
+			t.Errorf("CleanPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		CleanPath("/a/b/c/")
+		CleanPath("/a/b/c")
+	})
+	if allocs != 0 {
+		t.Errorf("CleanPath() of clean path allocates %v times", allocs)
+	}
+}
+
+func TestRouter_MatchNormalizePath(t *testing.T) {
+	r := buildRouter([]string{"/a/b", "/c/", "/static/*filepath"})
+	r.NormalizePath = true
+	tests := []struct {
+		path           string
+		want           bool
+		wantValue      int
+		wantNormalized string
+		wantParams     Params
+	}{
+		{path: "/a/b", want: true, wantValue: 100},
+		{path: "/a//b", want: true, wantValue: 100, wantNormalized: "/a/b"},
+		{path: "/a/./b", want: true, wantValue: 100, wantNormalized: "/a/b"},
+		{path: "/x/../a/b", want: true, wantValue: 100, wantNormalized: "/a/b"},
+		{path: "/c/d/..", want: true, wantValue: 101, wantNormalized: "/c/"},
+		{path: "/static/../../etc/passwd", want: false, wantNormalized: "/etc/passwd"},
+		{path: "/static/css/../js/a.js", want: true, wantValue: 102, wantNormalized: "/static/js/a.js", wantParams: Params{{Key: "filepath", Value: "js/a.js"}}},
+	}
+	for _, tt := range tests {
+		res := MatchResult[int]{NormalizedPath: "stale"}
+		if got := r.Match(tt.path, &res); got != tt.want {
+			t.Errorf("Router.Match(%q) = %v, want %v", tt.path, got, tt.want)
+			continue
+		}
+		if res.NormalizedPath != tt.wantNormalized {
+			t.Errorf("Router.Match(%q) NormalizedPath got %q, want %q", tt.path, res.NormalizedPath, tt.wantNormalized)
+		}
+		if tt.want && (res.Value != tt.wantValue || len(res.Params) != len(tt.wantParams)) {
+			t.Errorf("Router.Match(%q) got %+v", tt.path, res)
+		}
+		for i := range tt.wantParams {
+			if res.Params[i] != tt.wantParams[i] {
+				t.Errorf("Router.Match(%q) params got %+v, want %+v", tt.path, res.Params, tt.wantParams)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package pathrouter
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentRouter 允许在 Match 的同时修改路由
+// 读取时无锁加载当前的 Router，修改时在副本上进行并原子地发布，
+// 由于 Router 的修改只复制被修改路径上的节点，副本之间共享其余的节点
+type ConcurrentRouter[T any] struct {
+	mu sync.Mutex
+	r  atomic.Pointer[Router[T]]
+}
+
+// NewConcurrentRouter 以 r 的副本作为初始路由，之后对 r 的修改不会影响返回值
+func NewConcurrentRouter[T any](r *Router[T]) *ConcurrentRouter[T] {
+	c := &ConcurrentRouter[T]{}
+	if r != nil {
+		cp := *r
+		c.r.Store(&cp)
+	}
+	return c
+}
+
+// Load 返回当前 Router 的快照，调用方不能修改返回值
+func (c *ConcurrentRouter[T]) Load() *Router[T] {
+	r := c.r.Load()
+	if r == nil {
+		return &Router[T]{}
+	}
+	return r
+}
+
+func (c *ConcurrentRouter[T]) Match(path string, res *MatchResult[T]) bool {
+	r := c.r.Load()
+	if r == nil {
+		return false
+	}
+	return r.Match(path, res)
+}
+
+// Modify 在当前 Router 的副本上执行 fn，fn 成功时发布副本，失败时丢弃全部修改
+func (c *ConcurrentRouter[T]) Modify(fn func(r *Router[T]) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var r Router[T]
+	if old := c.r.Load(); old != nil {
+		r = *old
+	}
+	err := fn(&r)
+	if err != nil {
+		return err
+	}
+	c.r.Store(&r)
+	return nil
+}
+
+func (c *ConcurrentRouter[T]) Add(path string, value T) error {
+	return c.Modify(func(r *Router[T]) error {
+		return r.Add(path, value)
+	})
+}
+
+func (c *ConcurrentRouter[T]) Insert(path string, value T) error {
+	return c.Modify(func(r *Router[T]) error {
+		return r.Insert(path, value)
+	})
+}
+
+func (c *ConcurrentRouter[T]) Update(path string, value T) error {
+	return c.Modify(func(r *Router[T]) error {
+		return r.Update(path, value)
+	})
+}
+
+func (c *ConcurrentRouter[T]) Upsert(path string, value T) error {
+	return c.Modify(func(r *Router[T]) error {
+		return r.Upsert(path, value)
+	})
+}
+
+func (c *ConcurrentRouter[T]) Remove(path string) bool {
+	removed := false
+	c.Modify(func(r *Router[T]) error {
+		removed = r.Remove(path)
+		return nil
+	})
+	return removed
+}
+
+func (c *ConcurrentRouter[T]) AddAll(routes []Route[T]) error {
+	return c.Modify(func(r *Router[T]) error {
+		return r.AddAll(routes)
+	})
+}
+
+func (c *ConcurrentRouter[T]) Mount(prefix string, sub *Router[T]) error {
+	return c.Modify(func(r *Router[T]) error {
+		return r.Mount(prefix, sub)
+	})
+}
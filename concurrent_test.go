@@ -0,0 +1,117 @@
+package pathrouter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRouter_Snapshot(t *testing.T) {
+	r := buildRouter([]string{"/a", "/a/:b", "/c/*d"})
+	snapshot := *r
+	want := buildRouter([]string{"/a", "/a/:b", "/c/*d"})
+
+	r.Add("/ab", 200)
+	r.Add("/a/:b/c", 201)
+	r.Update("/a", 202)
+	r.Remove("/c/*d")
+	if !reflect.DeepEqual(&snapshot, want) {
+		t.Fatal("modifying Router changed the snapshot")
+	}
+
+	// 失败的修改不改变原有的树
+	root := r.root
+	if err := r.Add("/a/:x", 300); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Router.Add() error = %v, wantErr %v", err, ErrConflict)
+	}
+	if r.root != root {
+		t.Fatal("failed Router.Add() replaced the root")
+	}
+}
+
+func TestConcurrentRouter_Modify(t *testing.T) {
+	c := NewConcurrentRouter(buildRouter([]string{"/a"}))
+	err := c.Modify(func(r *Router[int]) error {
+		r.Add("/b", 101)
+		return r.Add("/:x/:y", 102)
+	})
+	if err != nil {
+		t.Fatalf("ConcurrentRouter.Modify() error = %v", err)
+	}
+	err = c.Modify(func(r *Router[int]) error {
+		r.Add("/c", 103)
+		return r.Add("/:z", 104)
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("ConcurrentRouter.Modify() error = %v, wantErr %v", err, ErrConflict)
+	}
+	var res MatchResult[int]
+	if c.Match("/c", &res) {
+		t.Fatal("failed ConcurrentRouter.Modify() was published")
+	}
+	if !c.Match("/b", &res) || res.Value != 101 {
+		t.Fatalf("ConcurrentRouter.Match() got %v, want %v", res.Value, 101)
+	}
+	if !c.Remove("/b") || c.Remove("/b") {
+		t.Fatal("ConcurrentRouter.Remove() result mismatch")
+	}
+
+	var zero ConcurrentRouter[int]
+	if zero.Match("/", &res) {
+		t.Fatal("empty ConcurrentRouter matched")
+	}
+	if err := zero.Insert("/", 100); err != nil {
+		t.Fatalf("ConcurrentRouter.Insert() error = %v", err)
+	}
+	if !zero.Match("/", &res) || res.Value != 100 {
+		t.Fatalf("ConcurrentRouter.Match() got %v, want %v", res.Value, 100)
+	}
+}
+
+func TestConcurrentRouter_Race(t *testing.T) {
+	c := &ConcurrentRouter[int]{}
+	for i, route := range githubRoutes {
+		c.Upsert(route.Path, i)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := MatchResult[int]{Params: make(Params, 0, 10)}
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for _, route := range githubRoutes {
+					path, _ := githubPath(route.Path)
+					res.Params = res.Params[:0]
+					if !c.Match(path, &res) {
+						t.Errorf("ConcurrentRouter.Match(%q) = false", path)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		path := fmt.Sprintf("/users/:user/extra%d/:id", i%20)
+		if i%40 < 20 {
+			if err := c.Insert(path, i); err != nil {
+				t.Errorf("ConcurrentRouter.Insert(%q) error = %v", path, err)
+			}
+		} else if !c.Remove(path) {
+			t.Errorf("ConcurrentRouter.Remove(%q) = false", path)
+		}
+		c.Update("/users/:user", i)
+	}
+	close(done)
+	wg.Wait()
+}
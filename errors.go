@@ -0,0 +1,47 @@
+package pathrouter
+
+import "fmt"
+
+// PatternError 描述无法解析的 pattern，errors.Is(err, ErrInvalidPath) 为 true
+type PatternError struct {
+	Pattern string
+	// Offset 是出错的位置在 Pattern 中的字节偏移
+	Offset int
+	Reason string
+	// Err 是导致错误的底层错误，例如正则表达式的编译错误
+	Err error
+}
+
+func (e *PatternError) Error() string {
+	msg := fmt.Sprintf("%s: %s at offset %d in %q", ErrInvalidPath, e.Reason, e.Offset, e.Pattern)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *PatternError) Is(target error) bool {
+	return target == ErrInvalidPath
+}
+
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError 描述与已有路由冲突的 pattern，errors.Is(err, ErrConflict) 为 true
+type ConflictError struct {
+	Pattern string
+	// Existing 是冲突位置上已有的一个路由
+	Existing string
+	// Offset 是冲突的位置在 Pattern 中的字节偏移
+	Offset int
+	Reason string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: %s at offset %d in %q, conflicts with %q", ErrConflict, e.Reason, e.Offset, e.Pattern, e.Existing)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
@@ -0,0 +1,80 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"regexp/syntax"
+	"testing"
+)
+
+func TestRouter_AddConflictError(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []string
+		want   ConflictError
+	}{
+		{name: "param-name", routes: []string{"/users/:id", "/users/:id/posts", "/users/:name/posts"}, want: ConflictError{Pattern: "/users/:name/posts", Existing: "/users/:id", Offset: 7, Reason: "param name differs"}},
+		{name: "param-constraint", routes: []string{"/a/:id{[0-9]+}/x", "/a/:id"}, want: ConflictError{Pattern: "/a/:id", Existing: "/a/:id{[0-9]+}/x", Offset: 3, Reason: "param constraint differs"}},
+		{name: "param-type", routes: []string{"/b/:id<int>", "/b/:id<uuid>"}, want: ConflictError{Pattern: "/b/:id<uuid>", Existing: "/b/:id<int>", Offset: 3, Reason: "param constraint differs"}},
+		{name: "ambiguous-literal", routes: []string{"/r/:from-:to", "/r/:from.:to"}, want: ConflictError{Pattern: "/r/:from.:to", Existing: "/r/:from-:to", Offset: 8, Reason: "ambiguous literal after param"}},
+		{name: "catch-all", routes: []string{"/s/:v/*x", "/s/:v/*y"}, want: ConflictError{Pattern: "/s/:v/*y", Existing: "/s/:v/*x", Offset: 6, Reason: "catch-all name differs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Router[int]{}
+			var err error
+			for _, route := range tt.routes {
+				err = r.Add(route, 0)
+			}
+			var ce *ConflictError
+			if !errors.As(err, &ce) || !errors.Is(err, ErrConflict) {
+				t.Fatalf("Router.Add() error = %v, want *ConflictError", err)
+			}
+			if *ce != tt.want {
+				t.Errorf("ConflictError got %+v, want %+v", *ce, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_AddPatternError(t *testing.T) {
+	tests := []struct {
+		name       string
+		route      string
+		wantOffset int
+		wantReason string
+	}{
+		{name: "missing-name", route: "/users/:", wantOffset: 8, wantReason: "missing param name"},
+		{name: "unclosed-constraint", route: "/users/:id{[0-9]+", wantOffset: 10, wantReason: "unclosed constraint"},
+		{name: "empty-constraint", route: "/a/:b{}", wantOffset: 5, wantReason: "empty constraint"},
+		{name: "empty-constraint-typed", route: "/a/:b<int>{}/c", wantOffset: 10, wantReason: "empty constraint"},
+		{name: "unclosed-type", route: "/users/:id<int", wantOffset: 10, wantReason: "unclosed type"},
+		{name: "unknown-type", route: "/:id<float>", wantOffset: 4, wantReason: `unknown type "float"`},
+		{name: "catch-all", route: "/a/*x/b", wantOffset: 5, wantReason: "catch-all must be at the end"},
+		{name: "catch-all-name", route: "/static/*file.txt", wantOffset: 13, wantReason: "invalid catch-all name"},
+		{name: "adjacent-params", route: "/files/:name:ext", wantOffset: 12, wantReason: "param must be followed by static text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Router[int]{}).Add(tt.route, 0)
+			var pe *PatternError
+			if !errors.As(err, &pe) || !errors.Is(err, ErrInvalidPath) {
+				t.Fatalf("Router.Add(%q) error = %v, want *PatternError", tt.route, err)
+			}
+			want := PatternError{Pattern: tt.route, Offset: tt.wantOffset, Reason: tt.wantReason}
+			if !reflect.DeepEqual(*pe, want) {
+				t.Errorf("PatternError got %+v, want %+v", *pe, want)
+			}
+		})
+	}
+
+	err := (&Router[int]{}).Add("/users/:id{[0-9+}", 0)
+	var se *syntax.Error
+	if !errors.As(err, &se) {
+		t.Fatalf("Router.Add() error = %v, want wrapped *syntax.Error", err)
+	}
+	want := `Invalid path: bad constraint at offset 10 in "/users/:id{[0-9+}": ` + se.Error()
+	if err.Error() != want {
+		t.Errorf("PatternError.Error() got %q, want %q", err.Error(), want)
+	}
+}
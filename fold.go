@@ -0,0 +1,225 @@
+package pathrouter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CaseFold 决定静态文本是否忽略大小写
+type CaseFold uint8
+
+const (
+	// FoldNone 区分大小写
+	FoldNone CaseFold = iota
+	// FoldASCII 忽略 ASCII 字母的大小写
+	FoldASCII
+	// FoldUnicode 按照 Unicode 简单折叠忽略大小写
+	FoldUnicode
+)
+
+// FindCaseInsensitivePath 按照 fold 忽略静态文本的大小写查找 path，
+// 返回静态文本替换为路由中的写法后的路径，param 的值保持不变
+func (r *Router[T]) FindCaseInsensitivePath(path string, fold CaseFold) (string, bool) {
+	if r.root == nil {
+		return "", false
+	}
+	var res MatchResult[T]
+	buf := make([]byte, 0, len(path)+1)
+	if r.root.foldLookup(path, &res, fold, &buf) == nil {
+		return "", false
+	}
+	return string(buf), true
+}
+
+// foldLookup 与 lookup 相同，但静态文本按照 fold 比较，buf 不为 nil 时记录规范的路径
+func (n *node[T]) foldLookup(path string, res *MatchResult[T], fold CaseFold, buf *[]byte) *node[T] {
+	if path == "" {
+		if n.end {
+			return n
+		}
+	} else {
+		// 不同大小写的静态子节点首字节不同，需要依次尝试
+		for _, child := range n.children {
+			if child.kind != staticKind {
+				break
+			}
+			found := child.foldStatic(child.path, path, res, fold, buf)
+			if found != nil {
+				return found
+			}
+		}
+	}
+
+	if !n.wildChild {
+		return nil
+	}
+
+	for _, child := range n.children {
+		switch child.kind {
+		case paramKind:
+			if path == "" {
+				continue
+			}
+			found := child.foldMatchParam(path, res, fold, buf)
+			if found != nil {
+				return found
+			}
+		case trailingKind:
+			if child.end {
+				appendBuf(buf, path)
+				res.Params = append(res.Params, Param{Key: child.key, Value: path})
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// foldStatic 按照 fold 比较 path 和静态节点 n 的文本 text，之后匹配 n 的子节点
+// 共享首字节的多字节字符会被节点分裂截断，此时将 text 与子节点的文本连接成完整的字符后再比较
+func (n *node[T]) foldStatic(text string, path string, res *MatchResult[T], fold CaseFold, buf *[]byte) *node[T] {
+	if fold == FoldUnicode && !n.end && splitsRune(text) {
+		for _, child := range n.children {
+			if child.kind != staticKind {
+				break
+			}
+			found := child.foldStatic(text+child.path, path, res, fold, buf)
+			if found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	l := foldPrefix(path, text, fold)
+	if l < 0 {
+		return nil
+	}
+	mark := appendBuf(buf, text)
+	found := n.foldLookup(path[l:], res, fold, buf)
+	if found == nil {
+		truncateBuf(buf, mark)
+	}
+	return found
+}
+
+// splitsRune 检查 s 是否以不完整的多字节字符结尾
+func splitsRune(s string) bool {
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			return !utf8.FullRuneInString(s[i:])
+		}
+	}
+	return false
+}
+
+// foldMatchParam 与 matchParam 相同，但 param 之后的静态文本按照 fold 比较
+func (n *node[T]) foldMatchParam(path string, res *MatchResult[T], fold CaseFold, buf *[]byte) *node[T] {
+	end := strings.IndexByte(path, '/')
+	if end < 0 {
+		end = len(path)
+	}
+	mark := len(res.Params)
+	for _, child := range n.children {
+		if child.path[0] == '/' {
+			continue
+		}
+		for i := 1; i < end; i++ {
+			if !n.accept(path[:i], res) {
+				continue
+			}
+			bufMark := appendBuf(buf, path[:i])
+			found := child.foldStatic(child.path, path[i:], res, fold, buf)
+			if found != nil {
+				return found
+			}
+			truncateBuf(buf, bufMark)
+			res.Params = res.Params[:mark]
+		}
+	}
+	if end > 0 && n.accept(path[:end], res) {
+		bufMark := appendBuf(buf, path[:end])
+		found := n.foldLookup(path[end:], res, fold, buf)
+		if found != nil {
+			return found
+		}
+		truncateBuf(buf, bufMark)
+		res.Params = res.Params[:mark]
+	}
+	return nil
+}
+
+func appendBuf(buf *[]byte, s string) int {
+	if buf == nil {
+		return 0
+	}
+	mark := len(*buf)
+	*buf = append(*buf, s...)
+	return mark
+}
+
+func truncateBuf(buf *[]byte, mark int) {
+	if buf != nil {
+		*buf = (*buf)[:mark]
+	}
+}
+
+// foldPrefix 检查 s 是否以 prefix 开头，返回 s 中匹配部分的长度，不匹配时返回 -1
+func foldPrefix(s string, prefix string, fold CaseFold) int {
+	switch fold {
+	case FoldASCII:
+		if len(s) < len(prefix) {
+			return -1
+		}
+		for i := 0; i < len(prefix); i++ {
+			if lowerASCII(s[i]) != lowerASCII(prefix[i]) {
+				return -1
+			}
+		}
+		return len(prefix)
+	case FoldUnicode:
+		i, j := 0, 0
+		for j < len(prefix) {
+			if i >= len(s) {
+				return -1
+			}
+			pr, pn := utf8.DecodeRuneInString(prefix[j:])
+			sr, sn := utf8.DecodeRuneInString(s[i:])
+			if pr == utf8.RuneError && pn <= 1 || sr == utf8.RuneError && sn <= 1 {
+				// 不完整的字符逐字节比较
+				if s[i] != prefix[j] {
+					return -1
+				}
+				i++
+				j++
+				continue
+			}
+			if pr != sr && !equalFoldRune(pr, sr) {
+				return -1
+			}
+			i += sn
+			j += pn
+		}
+		return i
+	}
+	if !strings.HasPrefix(s, prefix) {
+		return -1
+	}
+	return len(prefix)
+}
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func equalFoldRune(a rune, b rune) bool {
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
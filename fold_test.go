@@ -0,0 +1,114 @@
+package pathrouter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouter_FindCaseInsensitivePath(t *testing.T) {
+	r := buildRouter([]string{
+		"/users/:id",
+		"/users/new",
+		"/Repos/:owner/Settings",
+		"/files/:name.JSON",
+		"/static/*filepath",
+		"/straße/:id",
+		"/été/:x",
+		"/дом/:id",
+		"/мир",
+		"/p/:a-дом",
+		"/p/:a-дон",
+	})
+	tests := []struct {
+		path string
+		fold CaseFold
+		want string
+		ok   bool
+	}{
+		{path: "/USERS/42", fold: FoldASCII, want: "/users/42", ok: true},
+		{path: "/Users/New", fold: FoldASCII, want: "/users/new", ok: true},
+		{path: "/repos/Vizee/settings", fold: FoldASCII, want: "/Repos/Vizee/Settings", ok: true},
+		{path: "/FILES/Data.json", fold: FoldASCII, want: "/files/Data.JSON", ok: true},
+		{path: "/Static/CSS/A.css", fold: FoldASCII, want: "/static/CSS/A.css", ok: true},
+		{path: "/users/42/x", fold: FoldASCII, ok: false},
+		{path: "/STRASSE/1", fold: FoldUnicode, ok: false},
+		{path: "/STRAßE/1", fold: FoldASCII, want: "/straße/1", ok: true},
+		{path: "/ÉTÉ/1", fold: FoldASCII, ok: false},
+		{path: "/ÉTÉ/1", fold: FoldUnicode, want: "/été/1", ok: true},
+		{path: "/Été/1", fold: FoldUnicode, want: "/été/1", ok: true},
+		// 共享首字节的西里尔字母被节点分裂截断
+		{path: "/ДОМ/1", fold: FoldUnicode, want: "/дом/1", ok: true},
+		{path: "/МИР", fold: FoldUnicode, want: "/мир", ok: true},
+		{path: "/Мир", fold: FoldUnicode, want: "/мир", ok: true},
+		{path: "/p/X-ДОН", fold: FoldUnicode, want: "/p/X-дон", ok: true},
+		{path: "/p/X-ДОМ", fold: FoldUnicode, want: "/p/X-дом", ok: true},
+		{path: "/МИР", fold: FoldASCII, ok: false},
+		{path: "/МИРЫ", fold: FoldUnicode, ok: false},
+		{path: "/users/42", fold: FoldNone, want: "/users/42", ok: true},
+		{path: "/USERS/42", fold: FoldNone, ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := r.FindCaseInsensitivePath(tt.path, tt.fold)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Router.FindCaseInsensitivePath(%q, %v) got %q, %v, want %q, %v", tt.path, tt.fold, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRouter_MatchCaseFoldUnicode(t *testing.T) {
+	r := buildRouter([]string{"/дом", "/мир", "/мир/:id"})
+	r.CaseFold = FoldUnicode
+	tests := []struct {
+		path    string
+		want    bool
+		wantRes MatchResult[int]
+	}{
+		{path: "/ДОМ", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: "/дом"}},
+		{path: "/Мир", want: true, wantRes: MatchResult[int]{Value: 101, Pattern: "/мир"}},
+		{path: "/МИР/Б", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "Б"}}, Value: 102, Pattern: "/мир/:id"}},
+		{path: "/ДОМА", want: false},
+	}
+	for _, tt := range tests {
+		var res MatchResult[int]
+		if got := r.Match(tt.path, &res); got != tt.want {
+			t.Errorf("Router.Match(%q) = %v, want %v", tt.path, got, tt.want)
+			continue
+		}
+		if tt.want && !reflect.DeepEqual(res, tt.wantRes) {
+			t.Errorf("Router.Match(%q) got %+v, want %+v", tt.path, res, tt.wantRes)
+		}
+	}
+}
+
+func TestRouter_MatchCaseFold(t *testing.T) {
+	r := buildRouter([]string{"/users/:id", "/users/new", "/a/:b/C", "/A/:b/c"})
+	r.CaseFold = FoldASCII
+	tests := []struct {
+		path    string
+		want    bool
+		wantRes MatchResult[int]
+	}{
+		{path: "/users/new", want: true, wantRes: MatchResult[int]{Value: 101, Pattern: "/users/new"}},
+		{path: "/USERS/NEW", want: true, wantRes: MatchResult[int]{Value: 101, Pattern: "/users/new"}},
+		{path: "/Users/Bob", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "Bob"}}, Value: 100, Pattern: "/users/:id"}},
+		{path: "/a/x/C", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "b", Value: "x"}}, Value: 102, Pattern: "/a/:b/C"}},
+		{path: "/A/x/c", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "b", Value: "x"}}, Value: 103, Pattern: "/A/:b/c"}},
+		// 忽略大小写时静态子节点按首字节顺序尝试
+		{path: "/a/x/c", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "b", Value: "x"}}, Value: 103, Pattern: "/A/:b/c"}},
+		{path: "/USERS/", want: false, wantRes: MatchResult[int]{}},
+		{path: "/USERS/NEW/", want: false, wantRes: MatchResult[int]{TSR: true}},
+	}
+	for _, tt := range tests {
+		var res MatchResult[int]
+		if got := r.Match(tt.path, &res); got != tt.want {
+			t.Errorf("Router.Match(%q) = %v, want %v", tt.path, got, tt.want)
+			continue
+		}
+		if tt.want && !reflect.DeepEqual(res, tt.wantRes) {
+			t.Errorf("Router.Match(%q) got %+v, want %+v", tt.path, res, tt.wantRes)
+		}
+		if !tt.want && res.TSR != tt.wantRes.TSR {
+			t.Errorf("Router.Match(%q) TSR got %v, want %v", tt.path, res.TSR, tt.wantRes.TSR)
+		}
+	}
+}
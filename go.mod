@@ -1,3 +1,3 @@
 module github.com/vizee/pathrouter
 
-go 1.21.0
+go 1.23.0
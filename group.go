@@ -0,0 +1,62 @@
+package pathrouter
+
+import "errors"
+
+// Group 以相同的前缀向 Router 添加路由，并在添加前依次使用 decorators 转换值，
+// 例如为 handler 添加中间件
+// 嵌套的 Group 先应用自身的 decorators，再应用外层的，因此外层的中间件在最外侧
+// 同一组 Group 共享错误记录，可以在全部添加完成后通过 Err 检查
+type Group[T any] struct {
+	r          *Router[T]
+	parent     *Group[T]
+	prefix     string
+	decorators []func(T) T
+	errs       *[]error
+}
+
+// Group 返回以 prefix 为前缀的 Group，prefix 与 pattern 直接连接
+func (r *Router[T]) Group(prefix string, decorators ...func(T) T) *Group[T] {
+	return &Group[T]{r: r, prefix: prefix, decorators: decorators, errs: new([]error)}
+}
+
+// Group 返回嵌套的 Group，前缀为 g 的前缀与 prefix 连接
+func (g *Group[T]) Group(prefix string, decorators ...func(T) T) *Group[T] {
+	return &Group[T]{r: g.r, parent: g, prefix: g.prefix + prefix, decorators: decorators, errs: g.errs}
+}
+
+// Prefix 返回 g 的完整前缀
+func (g *Group[T]) Prefix() string {
+	return g.prefix
+}
+
+// decorate 按照从内到外的顺序应用 decorators，第一个 decorator 在最外侧
+func (g *Group[T]) decorate(value T) T {
+	for ; g != nil; g = g.parent {
+		for i := len(g.decorators) - 1; i >= 0; i-- {
+			value = g.decorators[i](value)
+		}
+	}
+	return value
+}
+
+func (g *Group[T]) record(err error) error {
+	if err != nil {
+		*g.errs = append(*g.errs, err)
+	}
+	return err
+}
+
+// Add 添加前缀与 path 连接后的路由，按照 Router 的 Duplicate 处理已存在的路由
+func (g *Group[T]) Add(path string, value T) error {
+	return g.record(g.r.Add(g.prefix+path, g.decorate(value)))
+}
+
+// AddNamed 添加前缀与 path 连接后的路由并关联 name
+func (g *Group[T]) AddNamed(name string, path string, value T) error {
+	return g.record(g.r.AddNamed(name, g.prefix+path, g.decorate(value)))
+}
+
+// Err 返回同一组 Group 中全部添加失败的错误，以 errors.Join 合并
+func (g *Group[T]) Err() error {
+	return errors.Join(*g.errs...)
+}
@@ -0,0 +1,85 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouter_Group(t *testing.T) {
+	r := &Router[string]{Duplicate: DuplicateError}
+	wrap := func(name string) func(string) string {
+		return func(v string) string {
+			return name + "(" + v + ")"
+		}
+	}
+
+	repo := r.Group("/repos/:owner/:repo", wrap("auth"), wrap("log"))
+	repo.Add("", "repo")
+	repo.AddNamed("issue", "/issues/:number", "issue")
+	hooks := repo.Group("/hooks", wrap("admin"))
+	hooks.Add("/:id", "hook")
+	plain := r.Group("/static")
+	plain.Add("/*filepath", "file")
+
+	if got := hooks.Prefix(); got != "/repos/:owner/:repo/hooks" {
+		t.Errorf("Group.Prefix() got %q", got)
+	}
+
+	tests := []struct {
+		path        string
+		wantValue   string
+		wantPattern string
+	}{
+		{path: "/repos/a/b", wantValue: "auth(log(repo))", wantPattern: "/repos/:owner/:repo"},
+		{path: "/repos/a/b/issues/1", wantValue: "auth(log(issue))", wantPattern: "/repos/:owner/:repo/issues/:number"},
+		{path: "/repos/a/b/hooks/2", wantValue: "auth(log(admin(hook)))", wantPattern: "/repos/:owner/:repo/hooks/:id"},
+		{path: "/static/a.css", wantValue: "file", wantPattern: "/static/*filepath"},
+	}
+	for _, tt := range tests {
+		var res MatchResult[string]
+		if !r.Match(tt.path, &res) {
+			t.Errorf("Router.Match(%q) = false", tt.path)
+			continue
+		}
+		if res.Value != tt.wantValue || res.Pattern != tt.wantPattern {
+			t.Errorf("Router.Match(%q) got %q %q, want %q %q", tt.path, res.Value, res.Pattern, tt.wantValue, tt.wantPattern)
+		}
+	}
+	if path, err := r.Build("issue", Params{{Key: "owner", Value: "a"}, {Key: "repo", Value: "b"}, {Key: "number", Value: "1"}}); err != nil || path != "/repos/a/b/issues/1" {
+		t.Errorf("Router.Build() got %q, %v", path, err)
+	}
+	if err := repo.Err(); err != nil {
+		t.Errorf("Group.Err() = %v", err)
+	}
+}
+
+func TestGroup_Err(t *testing.T) {
+	r := &Router[int]{Duplicate: DuplicateError}
+	g := r.Group("/users")
+	nested := g.Group("/:id")
+	other := r.Group("/other")
+
+	g.Add("/:id", 1)
+	if err := g.Add("/:id", 2); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Group.Add() error = %v, want %v", err, ErrDuplicate)
+	}
+	nested.Add(":x", 3)
+	nested.Add("/posts", 4)
+	other.Add("/:", 5)
+
+	errs := g.Err().(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 2 || !errors.Is(errs[0], ErrDuplicate) || !errors.Is(errs[1], ErrInvalidPath) {
+		t.Errorf("Group.Err() got %v", errs)
+	}
+	if !reflect.DeepEqual(nested.Err(), g.Err()) {
+		t.Errorf("nested Group.Err() differs from parent")
+	}
+	if err := other.Err(); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("Group.Err() error = %v, want %v", err, ErrInvalidPath)
+	}
+	var res MatchResult[int]
+	if !r.Match("/users/1/posts", &res) || res.Value != 4 {
+		t.Errorf("Router.Match() got %+v", res)
+	}
+}
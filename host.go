@@ -0,0 +1,227 @@
+package pathrouter
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// HostRouter 先按照 host 再按照 path 匹配路由
+// host 按照 . 分隔的标签匹配，标签可以是 :name 或包含 param 的文本，
+// 最左侧的标签可以是 * 或 *name，匹配一个或多个标签，
+// 同一位置优先匹配静态标签，其次 param，最后 *
+// host 转换为小写后匹配，pattern 中的静态文本必须是小写
+// 先确定 host 对应的路由再匹配 path，path 匹配失败时按照同样的优先级尝试其他匹配的 host，
+// 例如 acme.example.com 没有的 path 会继续在 :tenant.example.com 和 * 中匹配
+type HostRouter[T any] struct {
+	// Duplicate 是 Add 的重复路由策略，默认替换
+	Duplicate DuplicatePolicy
+
+	// host 的标签逆序后以 / 连接作为 path，例如 :tenant.example.com 对应 com/example/:tenant
+	hosts Router[*Router[T]]
+}
+
+// Add 添加 host 和 path 对应的路由，host 为 * 时匹配任意 host
+// host 的错误是 *PatternError 或 *ConflictError，其中的 pattern 和偏移针对 host 本身
+func (h *HostRouter[T]) Add(host string, path string, value T) error {
+	rev, starts, err := reverseHostPattern(host)
+	if err != nil {
+		return err
+	}
+	segs, err := parsePath[*Router[T]](rev)
+	if err != nil {
+		return hostError(err, host, rev, starts)
+	}
+	offset := 0
+	for _, seg := range segs {
+		if seg.kind == staticKind && strings.ToLower(seg.path) != seg.path {
+			i := max(strings.IndexFunc(seg.path, unicode.IsUpper), 0)
+			return &PatternError{Pattern: host, Offset: hostOffset(rev, starts, offset+i), Reason: "host is not lowercase"}
+		}
+		offset += len(seg.path)
+	}
+	// 复制 host 对应的路由，修改失败时原有的路由保持不变
+	var r Router[T]
+	if stack := h.hosts.find(segs); stack != nil && stack[len(stack)-1].end {
+		r = *stack[len(stack)-1].value
+	}
+	r.Duplicate = h.Duplicate
+	err = r.Add(path, value)
+	if err != nil {
+		return err
+	}
+	err = h.hosts.Upsert(rev, &r)
+	if err != nil {
+		return hostError(err, host, rev, starts)
+	}
+	return nil
+}
+
+// Remove 删除 host 和 path 对应的路由，返回路由是否存在
+func (h *HostRouter[T]) Remove(host string, path string) bool {
+	rev, _, err := reverseHostPattern(host)
+	if err != nil {
+		return false
+	}
+	segs, err := parsePath[*Router[T]](rev)
+	if err != nil {
+		return false
+	}
+	stack := h.hosts.find(segs)
+	if stack == nil || !stack[len(stack)-1].end {
+		return false
+	}
+	r := *stack[len(stack)-1].value
+	if !r.Remove(path) {
+		return false
+	}
+	if r.root == nil {
+		h.hosts.Remove(rev)
+	} else {
+		h.hosts.Upsert(rev, &r)
+	}
+	return true
+}
+
+// Match 匹配 host 和 path，host 可以包含端口，res.Params 依次包含 host 和 path 的 param
+// * 匹配的值是以 . 连接的标签，res.Pattern 是 path 的 pattern
+func (h *HostRouter[T]) Match(host string, path string, res *MatchResult[T]) bool {
+	rev, ok := reverseHost(host)
+	if !ok || h.hosts.root == nil {
+		res.Pattern = ""
+		res.TSR = false
+		return false
+	}
+	mark := len(res.Params)
+	hres := MatchResult[*Router[T]]{Params: res.Params}
+	tsr := false
+	found := h.hosts.root.lookup(rev, &hres, func(n *node[*Router[T]]) bool {
+		res.Params = hres.Params
+		for i := mark; i < len(res.Params); i++ {
+			if strings.IndexByte(res.Params[i].Value, '/') >= 0 {
+				res.Params[i].Value = reverseLabels(res.Params[i].Value)
+			}
+		}
+		ok := n.value.Match(path, res)
+		tsr = tsr || res.TSR
+		hres.Params = res.Params
+		return ok
+	})
+	if found == nil {
+		res.Params = hres.Params
+		res.Pattern = ""
+		res.TSR = tsr
+		return false
+	}
+	return true
+}
+
+// reverseHost 去掉 host 的端口和末尾的 .，转换为小写，并将标签逆序后以 / 连接
+// 空的 host 只能匹配 *，包含空标签的 host 返回 false
+func reverseHost(host string) (string, bool) {
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && strings.IndexByte(host[i:], ']') < 0 {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(host, ".")
+	if host == "" {
+		return "", true
+	}
+	buf := make([]byte, 0, len(host))
+	for end := len(host); end >= 0; {
+		i := strings.LastIndexByte(host[:end], '.')
+		if i+1 == end {
+			return "", false
+		}
+		for j := i + 1; j < end; j++ {
+			buf = append(buf, lowerASCII(host[j]))
+		}
+		if i < 0 {
+			break
+		}
+		buf = append(buf, '/')
+		end = i
+	}
+	return string(buf), true
+}
+
+// reverseLabels 将 * 匹配的逆序标签还原为以 . 连接的 host
+func reverseLabels(s string) string {
+	labels := strings.Split(s, "/")
+	slices.Reverse(labels)
+	return strings.Join(labels, ".")
+}
+
+// reverseHostPattern 将 host 的 pattern 转换为逆序的 path，{} 中的 . 不作为分隔符，
+// 同时返回逆序后每个标签在 host 中的起始位置
+func reverseHostPattern(host string) (string, []int, error) {
+	if host == "" {
+		return "", nil, &PatternError{Pattern: host, Reason: "empty host"}
+	}
+	if i := strings.IndexByte(host, '/'); i >= 0 {
+		return "", nil, &PatternError{Pattern: host, Offset: i, Reason: "host contains /"}
+	}
+	var labels []string
+	var starts []int
+	depth := 0
+	start := 0
+	for i := 0; i <= len(host); i++ {
+		if i < len(host) {
+			switch host[i] {
+			case '{':
+				depth++
+				continue
+			case '}':
+				depth--
+				continue
+			case '*':
+				// * 逆序后必须在末尾，并且不能与其他文本组成标签
+				if depth == 0 && (start != 0 || i != start) {
+					return "", nil, &PatternError{Pattern: host, Offset: i, Reason: "catch-all must start the leftmost label"}
+				}
+				continue
+			case '.':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		label := host[start:i]
+		if label == "" {
+			return "", nil, &PatternError{Pattern: host, Offset: start, Reason: "empty label"}
+		}
+		labels = append(labels, label)
+		starts = append(starts, start)
+		start = i + 1
+	}
+	slices.Reverse(labels)
+	slices.Reverse(starts)
+	return strings.Join(labels, "/"), starts, nil
+}
+
+// hostOffset 将逆序的 path 中的偏移转换为 host 中的偏移
+func hostOffset(rev string, starts []int, offset int) int {
+	pos := 0
+	for k, label := range strings.Split(rev, "/") {
+		if offset <= pos+len(label) {
+			return starts[k] + offset - pos
+		}
+		pos += len(label) + 1
+	}
+	return offset
+}
+
+// hostError 将 parsePath 和 insert 针对逆序的 path 返回的错误转换为针对 host 的错误
+func hostError(err error, host string, rev string, starts []int) error {
+	switch e := err.(type) {
+	case *PatternError:
+		e.Pattern = host
+		e.Offset = hostOffset(rev, starts, e.Offset)
+	case *ConflictError:
+		e.Pattern = host
+		e.Offset = hostOffset(rev, starts, e.Offset)
+		e.Existing = reverseLabels(e.Existing)
+	}
+	return err
+}
@@ -0,0 +1,172 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHostRouter_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		wantErr error
+	}{
+		{name: "static", host: "example.com"},
+		{name: "param", host: ":tenant.example.com"},
+		{name: "param-constraint", host: ":v{v[0-9]+.x}.example.com"},
+		{name: "trailing", host: "*.example.com"},
+		{name: "trailing-named", host: "*sub.example.com"},
+		{name: "any", host: "*"},
+		{name: "empty", host: "", wantErr: ErrInvalidPath},
+		{name: "empty-label", host: "a..com", wantErr: ErrInvalidPath},
+		{name: "slash", host: "a/b.com", wantErr: ErrInvalidPath},
+		{name: "trailing-middle", host: "a.*.com", wantErr: ErrInvalidPath},
+		{name: "trailing-prefixed", host: "a*.example.com", wantErr: ErrInvalidPath},
+		{name: "trailing-suffixed", host: "*{x}.example.com", wantErr: ErrInvalidPath},
+		{name: "uppercase", host: "API.example.com", wantErr: ErrInvalidPath},
+		{name: "uppercase-param", host: ":Tenant.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h HostRouter[int]
+			err := h.Add(tt.host, "/", 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("HostRouter.Add(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHostRouter_Match(t *testing.T) {
+	var h HostRouter[int]
+	routes := []struct {
+		host string
+		path string
+	}{
+		{host: "example.com", path: "/"},
+		{host: "api.example.com", path: "/users/:id"},
+		{host: ":tenant.example.com", path: "/users/:id"},
+		{host: ":tenant.example.com", path: "/"},
+		{host: "*sub.example.org", path: "/*path"},
+		{host: "*", path: "/health"},
+		{host: "v:major.api.example.net", path: "/"},
+	}
+	for i, route := range routes {
+		err := h.Add(route.host, route.path, 100+i)
+		if err != nil {
+			t.Fatalf("HostRouter.Add(%q, %q) error = %v", route.host, route.path, err)
+		}
+	}
+
+	tests := []struct {
+		host    string
+		path    string
+		want    bool
+		wantRes MatchResult[int]
+	}{
+		{host: "example.com", path: "/", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: "/"}},
+		{host: "Example.COM:8080", path: "/", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: "/"}},
+		{host: "example.com.", path: "/", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: "/"}},
+		{host: "api.example.com", path: "/users/1", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "1"}}, Value: 101, Pattern: "/users/:id"}},
+		{host: "acme.example.com", path: "/users/1", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "tenant", Value: "acme"}, {Key: "id", Value: "1"}}, Value: 102, Pattern: "/users/:id"}},
+		{host: "Acme.example.com", path: "/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "tenant", Value: "acme"}}, Value: 103, Pattern: "/"}},
+		{host: "a.b.example.org", path: "/x/y", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "sub", Value: "a.b"}, {Key: "path", Value: "x/y"}}, Value: 104, Pattern: "/*path"}},
+		{host: "a.example.org", path: "/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "sub", Value: "a"}, {Key: "path", Value: ""}}, Value: 104, Pattern: "/*path"}},
+		{host: "localhost", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "localhost"}}, Value: 105, Pattern: "/health"}},
+		{host: "[::1]:8080", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "[::1]"}}, Value: 105, Pattern: "/health"}},
+		{host: "v2.api.example.net", path: "/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "major", Value: "2"}}, Value: 106, Pattern: "/"}},
+		// path 匹配失败时回退到其他 host
+		{host: "api.example.com", path: "/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "tenant", Value: "api"}}, Value: 103, Pattern: "/"}},
+		{host: "api.example.com", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "api.example.com"}}, Value: 105, Pattern: "/health"}},
+		{host: "acme.example.com", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "acme.example.com"}}, Value: 105, Pattern: "/health"}},
+		{host: "example.com", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "example.com"}}, Value: 105, Pattern: "/health"}},
+		{host: "a.example.org", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "sub", Value: "a"}, {Key: "path", Value: "health"}}, Value: 104, Pattern: "/*path"}},
+		{host: "api.example.com", path: "/users", want: false},
+		{host: "a.b.example.com", path: "/", want: false},
+		{host: "example.org", path: "/", want: false},
+		// 包含空标签的 host 不匹配
+		{host: ".example.org", path: "/", want: false},
+		{host: "a..example.org", path: "/", want: false},
+		{host: "..", path: "/health", want: false},
+		{host: "", path: "/health", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: ""}}, Value: 105, Pattern: "/health"}},
+	}
+	for _, tt := range tests {
+		var res MatchResult[int]
+		if got := h.Match(tt.host, tt.path, &res); got != tt.want {
+			t.Errorf("HostRouter.Match(%q, %q) = %v, want %v", tt.host, tt.path, got, tt.want)
+			continue
+		}
+		if tt.want && !reflect.DeepEqual(res, tt.wantRes) {
+			t.Errorf("HostRouter.Match(%q, %q) got %+v, want %+v", tt.host, tt.path, res, tt.wantRes)
+		}
+	}
+}
+
+func TestHostRouter_AddError(t *testing.T) {
+	tests := []struct {
+		host string
+		want PatternError
+	}{
+		{host: "", want: PatternError{Pattern: "", Offset: 0, Reason: "empty host"}},
+		{host: "a/b.com", want: PatternError{Pattern: "a/b.com", Offset: 1, Reason: "host contains /"}},
+		{host: "a..com", want: PatternError{Pattern: "a..com", Offset: 2, Reason: "empty label"}},
+		{host: "example.*", want: PatternError{Pattern: "example.*", Offset: 8, Reason: "catch-all must start the leftmost label"}},
+		{host: "a.b*.com", want: PatternError{Pattern: "a.b*.com", Offset: 3, Reason: "catch-all must start the leftmost label"}},
+		{host: "a*.example.com", want: PatternError{Pattern: "a*.example.com", Offset: 1, Reason: "catch-all must start the leftmost label"}},
+		{host: "*a-b.example.com", want: PatternError{Pattern: "*a-b.example.com", Offset: 2, Reason: "invalid catch-all name"}},
+		{host: "API.example.com", want: PatternError{Pattern: "API.example.com", Offset: 0, Reason: "host is not lowercase"}},
+		{host: "api.Example.com", want: PatternError{Pattern: "api.Example.com", Offset: 4, Reason: "host is not lowercase"}},
+		{host: "x.:.example.com", want: PatternError{Pattern: "x.:.example.com", Offset: 3, Reason: "missing param name"}},
+		{host: ":a:b.example.com", want: PatternError{Pattern: ":a:b.example.com", Offset: 2, Reason: "param must be followed by static text"}},
+		{host: "api.:v{}.com", want: PatternError{Pattern: "api.:v{}.com", Offset: 6, Reason: "empty constraint"}},
+	}
+	for _, tt := range tests {
+		var h HostRouter[int]
+		err := h.Add(tt.host, "/", 1)
+		var pe *PatternError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("HostRouter.Add(%q) error = %v, want *PatternError", tt.host, err)
+			continue
+		}
+		if !reflect.DeepEqual(*pe, tt.want) {
+			t.Errorf("HostRouter.Add(%q) got %+v, want %+v", tt.host, *pe, tt.want)
+		}
+	}
+
+	var h HostRouter[int]
+	h.Add("www.:tenant.example.com", "/", 1)
+	err := h.Add("api.:org.example.com", "/", 2)
+	var ce *ConflictError
+	if !errors.As(err, &ce) {
+		t.Fatalf("HostRouter.Add() error = %v, want *ConflictError", err)
+	}
+	want := ConflictError{Pattern: "api.:org.example.com", Existing: "www.:tenant.example.com", Offset: 4, Reason: "param name differs"}
+	if *ce != want {
+		t.Errorf("HostRouter.Add() got %+v, want %+v", *ce, want)
+	}
+}
+
+func TestHostRouter_Remove(t *testing.T) {
+	h := HostRouter[int]{Duplicate: DuplicateError}
+	h.Add(":tenant.example.com", "/a", 1)
+	h.Add(":tenant.example.com", "/b", 2)
+	if err := h.Add(":tenant.example.com", "/a", 3); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("HostRouter.Add() duplicate error = %v", err)
+	}
+
+	var res MatchResult[int]
+	if !h.Remove(":tenant.example.com", "/a") || h.Match("x.example.com", "/a", &res) {
+		t.Errorf("HostRouter.Remove() route still matches")
+	}
+	if h.Remove(":tenant.example.com", "/a") || h.Remove("example.com", "/b") {
+		t.Errorf("HostRouter.Remove() = true for missing route")
+	}
+	res = MatchResult[int]{}
+	if !h.Match("x.example.com", "/b", &res) || res.Value != 2 {
+		t.Errorf("HostRouter.Match() after Remove got %+v", res)
+	}
+	if !h.Remove(":tenant.example.com", "/b") || h.hosts.root != nil {
+		t.Errorf("HostRouter.Remove() left empty host")
+	}
+}
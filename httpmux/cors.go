@@ -0,0 +1,60 @@
+package httpmux
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy 为预检请求设置响应头，allow 是路径可以使用的方法，格式与 Allow 响应头相同
+// 响应状态由 ServeMux 写入
+type CORSPolicy interface {
+	Preflight(w http.ResponseWriter, r *http.Request, allow string)
+}
+
+// CORS 是一个简单的 CORSPolicy
+type CORS struct {
+	// AllowOrigins 是允许的来源，包含 * 时允许任意来源
+	AllowOrigins []string
+	// AllowHeaders 是允许的请求头，为空时允许预检请求中的全部请求头
+	AllowHeaders     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func (c *CORS) Preflight(w http.ResponseWriter, r *http.Request, allow string) {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	origin := r.Header.Get("Origin")
+	wildcard := slices.Contains(c.AllowOrigins, "*")
+	if !wildcard && !slices.Contains(c.AllowOrigins, origin) {
+		return
+	}
+	method := r.Header.Get("Access-Control-Request-Method")
+	if !slices.Contains(strings.Split(allow, ", "), method) {
+		return
+	}
+
+	if wildcard && !c.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if c.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	h.Set("Access-Control-Allow-Methods", allow)
+	if len(c.AllowHeaders) != 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(c.AllowHeaders, ", "))
+	} else if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		h.Set("Access-Control-Allow-Headers", headers)
+	}
+	if c.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge/time.Second)))
+	}
+}
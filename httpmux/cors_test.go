@@ -0,0 +1,120 @@
+package httpmux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServeMux_Options(t *testing.T) {
+	m := NewServeMux()
+	m.Handle("GET", "/users/:id", echoParams("get-user"))
+	m.Handle("PUT", "/users/:id", echoParams("put-user"))
+	m.Handle("POST", "/users", echoParams("create-user"))
+	m.Handle("OPTIONS", "/custom", echoParams("custom-options"))
+
+	tests := []struct {
+		path      string
+		wantCode  int
+		wantAllow string
+		wantBody  string
+	}{
+		{path: "/users/42", wantCode: 204, wantAllow: "GET, HEAD, OPTIONS, PUT"},
+		{path: "/users", wantCode: 204, wantAllow: "OPTIONS, POST"},
+		{path: "*", wantCode: 204, wantAllow: "GET, HEAD, OPTIONS, POST, PUT"},
+		{path: "/custom", wantCode: 200, wantBody: "custom-options"},
+		{path: "/unknown", wantCode: 404, wantBody: "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("OPTIONS", "/", nil)
+			r.URL.Path = tt.path
+			m.ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Errorf("status got %d, want %d", w.Code, tt.wantCode)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow got %q, want %q", allow, tt.wantAllow)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body got %q, want %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+
+	// 只有 OPTIONS * 表示全部方法
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.URL.Path = "*"
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound || w.Header().Get("Allow") != "" {
+		t.Errorf("GET * got %d, Allow %q, want %d", w.Code, w.Header().Get("Allow"), http.StatusNotFound)
+	}
+	if allow := m.Allowed("*"); allow != "" {
+		t.Errorf("ServeMux.Allowed(%q) got %q, want %q", "*", allow, "")
+	}
+}
+
+func TestServeMux_CORS(t *testing.T) {
+	m := NewServeMux()
+	m.Handle("GET", "/users/:id", echoParams("get-user"))
+	m.Handle("DELETE", "/users/:id", echoParams("delete-user"))
+
+	tests := []struct {
+		name        string
+		cors        *CORS
+		origin      string
+		method      string
+		headers     string
+		wantOrigin  string
+		wantHeaders string
+		wantCreds   string
+		wantMaxAge  string
+	}{
+		{name: "wildcard", cors: &CORS{AllowOrigins: []string{"*"}}, origin: "https://a.example", method: "DELETE", wantOrigin: "*"},
+		{name: "origin", cors: &CORS{AllowOrigins: []string{"https://a.example"}, MaxAge: time.Hour}, origin: "https://a.example", method: "GET", wantOrigin: "https://a.example", wantMaxAge: "3600"},
+		{name: "credentials", cors: &CORS{AllowOrigins: []string{"*"}, AllowCredentials: true}, origin: "https://a.example", method: "GET", wantOrigin: "https://a.example", wantCreds: "true"},
+		{name: "headers", cors: &CORS{AllowOrigins: []string{"*"}}, origin: "https://a.example", method: "GET", headers: "X-Token", wantOrigin: "*", wantHeaders: "X-Token"},
+		{name: "allow-headers", cors: &CORS{AllowOrigins: []string{"*"}, AllowHeaders: []string{"X-A", "X-B"}}, origin: "https://a.example", method: "GET", headers: "X-Token", wantOrigin: "*", wantHeaders: "X-A, X-B"},
+		{name: "bad-origin", cors: &CORS{AllowOrigins: []string{"https://a.example"}}, origin: "https://b.example", method: "GET"},
+		{name: "bad-method", cors: &CORS{AllowOrigins: []string{"*"}}, origin: "https://a.example", method: "POST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.CORS = tt.cors
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("OPTIONS", "/users/42", nil)
+			r.Header.Set("Origin", tt.origin)
+			r.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				r.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			m.ServeHTTP(w, r)
+			if w.Code != http.StatusNoContent {
+				t.Errorf("status got %d, want %d", w.Code, http.StatusNoContent)
+			}
+			h := w.Header()
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin got %q, want %q", got, tt.wantOrigin)
+			}
+			wantMethods := ""
+			if tt.wantOrigin != "" {
+				wantMethods = "DELETE, GET, HEAD, OPTIONS"
+			}
+			if got := h.Get("Access-Control-Allow-Methods"); got != wantMethods {
+				t.Errorf("Access-Control-Allow-Methods got %q, want %q", got, wantMethods)
+			}
+			if got := h.Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Access-Control-Allow-Headers got %q, want %q", got, tt.wantHeaders)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials"); got != tt.wantCreds {
+				t.Errorf("Access-Control-Allow-Credentials got %q, want %q", got, tt.wantCreds)
+			}
+			if got := h.Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Access-Control-Max-Age got %q, want %q", got, tt.wantMaxAge)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package httpmux 基于 pathrouter 实现按 HTTP 方法区分路由的 http.Handler
+package httpmux
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/vizee/pathrouter"
+)
+
+type paramsKey struct{}
+
+// ParamsFromContext 返回 ServeMux 匹配到的 param
+func ParamsFromContext(ctx context.Context) pathrouter.Params {
+	ps, _ := ctx.Value(paramsKey{}).(pathrouter.Params)
+	return ps
+}
+
+// ServeMux 为每个 HTTP 方法维护一个 Router
+// Handle 需要在 ServeHTTP 之前完成调用
+type ServeMux struct {
+	// NotFound 处理没有匹配到路由的请求，默认为 http.NotFound
+	NotFound http.Handler
+	// MethodNotAllowed 处理路径存在但方法不匹配的请求，默认返回 405
+	// 调用前已经设置了 Allow 响应头
+	MethodNotAllowed http.Handler
+	// CORS 处理自动响应的 OPTIONS 中的预检请求
+	CORS CORSPolicy
+	// RedirectTrailingSlash 为 true 时，如果添加或删除末尾的 / 后能够匹配，则重定向到该路径
+	RedirectTrailingSlash bool
+	// RedirectCleanPath 为 true 时，在匹配前使用 CleanPath 规范化路径，路径被修改时重定向到规范的路径，
+	// 避免 .. 等片段进入 param 和 *
+	RedirectCleanPath bool
+	// RedirectFixedPath 为 true 时，如果规范化路径并忽略大小写后能够匹配，则重定向到修正后的路径
+	// 同时启用 RedirectCleanPath 的行为
+	RedirectFixedPath bool
+
+	trees map[string]*pathrouter.Router[http.Handler]
+	// 已注册的方法，按字典序排列
+	methods []string
+}
+
+func NewServeMux() *ServeMux {
+	return &ServeMux{}
+}
+
+// Handle 注册 method 和 pattern 对应的 handler，method 为空时匹配任意方法，
+// 优先级低于指定方法的路由
+func (m *ServeMux) Handle(method string, pattern string, handler http.Handler) error {
+	if m.trees == nil {
+		m.trees = make(map[string]*pathrouter.Router[http.Handler])
+	}
+	tree := m.trees[method]
+	if tree == nil {
+		tree = &pathrouter.Router[http.Handler]{Duplicate: pathrouter.DuplicateError}
+		m.trees[method] = tree
+		if method != "" {
+			i, _ := slices.BinarySearch(m.methods, method)
+			m.methods = slices.Insert(m.methods, i, method)
+		}
+	}
+	return tree.Add(pattern, handler)
+}
+
+// HandlePattern 使用 http.ServeMux 的 pattern 注册 handler，例如 "GET /users/{id}"
+// 不支持包含 host 的 pattern
+func (m *ServeMux) HandlePattern(pattern string, handler http.Handler) error {
+	method, host, path, err := pathrouter.ParseServeMuxPattern(pattern)
+	if err != nil {
+		return err
+	}
+	if host != "" {
+		return fmt.Errorf("%w: host is not supported in %q", pathrouter.ErrInvalidPath, pattern)
+	}
+	return m.Handle(method, path, handler)
+}
+
+func (m *ServeMux) HandleFunc(method string, pattern string, handler func(http.ResponseWriter, *http.Request)) error {
+	return m.Handle(method, pattern, http.HandlerFunc(handler))
+}
+
+// Lookup 返回 method 和 path 对应的 handler 和 param
+func (m *ServeMux) Lookup(method string, path string) (http.Handler, pathrouter.Params, bool) {
+	var res pathrouter.MatchResult[http.Handler]
+	if !m.match(method, path, &res) {
+		return nil, nil, false
+	}
+	return res.Value, res.Params, true
+}
+
+func (m *ServeMux) match(method string, path string, res *pathrouter.MatchResult[http.Handler]) bool {
+	tree := m.trees[method]
+	if tree == nil {
+		res.TSR = false
+		return false
+	}
+	return tree.Match(path, res)
+}
+
+func (m *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 与 http.ServeMux 一致，CONNECT 的路径不做规范化，OPTIONS * 不是路径
+	if (m.RedirectCleanPath || m.RedirectFixedPath) && r.Method != http.MethodConnect && r.URL.Path != "*" {
+		if clean := pathrouter.CleanPath(r.URL.Path); clean != r.URL.Path {
+			u := *r.URL
+			u.Path = clean
+			if m.RedirectFixedPath {
+				// 一次重定向到最终的路径
+				if fixed, ok := m.fixPath(r.Method, clean); ok {
+					u.Path = fixed
+				}
+			}
+			u.RawPath = ""
+			redirect(w, r, &u)
+			return
+		}
+	}
+
+	var res pathrouter.MatchResult[http.Handler]
+	ok := m.match(r.Method, r.URL.Path, &res)
+	tsr := res.TSR
+	if !ok && r.Method == http.MethodHead {
+		// 没有注册 HEAD 时使用 GET 的 handler
+		ok = m.match(http.MethodGet, r.URL.Path, &res)
+		tsr = tsr || res.TSR
+	}
+	if !ok {
+		ok = m.match("", r.URL.Path, &res)
+		tsr = tsr || res.TSR
+	}
+	if ok {
+		if len(res.Params) != 0 {
+			r = r.WithContext(context.WithValue(r.Context(), paramsKey{}, res.Params))
+			// 与 http.ServeMux 一致，可以通过 Request.PathValue 获取 param
+			for _, p := range res.Params {
+				r.SetPathValue(p.Key, p.Value)
+			}
+		}
+		// 用于监控和日志，与 http.ServeMux 不同，不包含 method
+		r.Pattern = res.Pattern
+		res.Value.ServeHTTP(w, r)
+		return
+	}
+
+	if tsr && m.RedirectTrailingSlash && r.Method != http.MethodConnect {
+		u := *r.URL
+		if strings.HasSuffix(u.Path, "/") {
+			u.Path = u.Path[:len(u.Path)-1]
+		} else {
+			u.Path += "/"
+		}
+		u.RawPath = ""
+		redirect(w, r, &u)
+		return
+	}
+
+	if m.RedirectFixedPath && r.Method != http.MethodConnect {
+		if fixed, ok := m.fixPath(r.Method, r.URL.Path); ok {
+			u := *r.URL
+			u.Path = fixed
+			u.RawPath = ""
+			redirect(w, r, &u)
+			return
+		}
+	}
+
+	// OPTIONS * 询问服务器支持的全部方法
+	if allow := m.allowed(r.URL.Path, r.Method == http.MethodOptions && r.URL.Path == "*"); allow != "" {
+		w.Header().Set("Allow", allow)
+		if r.Method == http.MethodOptions {
+			// 没有注册 OPTIONS 时自动响应
+			if m.CORS != nil && r.Header.Get("Origin") != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+				m.CORS.Preflight(w, r, allow)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if m.MethodNotAllowed != nil {
+			m.MethodNotAllowed.ServeHTTP(w, r)
+		} else {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if m.NotFound != nil {
+		m.NotFound.ServeHTTP(w, r)
+	} else {
+		http.NotFound(w, r)
+	}
+}
+
+// fixPath 使用 CleanPath 规范化 path，并在 method 的路由中忽略大小写查找
+func (m *ServeMux) fixPath(method string, path string) (string, bool) {
+	clean := pathrouter.CleanPath(path)
+	methods := []string{method, ""}
+	if method == http.MethodHead {
+		methods = append(methods, http.MethodGet)
+	}
+	for _, method := range methods {
+		tree := m.trees[method]
+		if tree == nil {
+			continue
+		}
+		fixed, ok := tree.FindCaseInsensitivePath(clean, pathrouter.FoldASCII)
+		if ok && fixed != path {
+			return fixed, true
+		}
+	}
+	return "", false
+}
+
+// Allowed 返回 path 可以使用的方法，格式与 Allow 响应头相同，path 不存在时返回空字符串
+// 注册了 GET 时同时允许 HEAD，OPTIONS 总是允许
+func (m *ServeMux) Allowed(path string) string {
+	return m.allowed(path, false)
+}
+
+// allowed 与 Allowed 相同，all 为 true 时返回全部已注册的方法
+func (m *ServeMux) allowed(path string, all bool) string {
+	var (
+		buf [10]string
+		res pathrouter.MatchResult[http.Handler]
+	)
+	allow := buf[:0]
+	for _, method := range m.methods {
+		res.Params = res.Params[:0]
+		if all || m.trees[method].Match(path, &res) {
+			allow = append(allow, method)
+		}
+	}
+	if len(allow) == 0 {
+		return ""
+	}
+	if slices.Contains(allow, http.MethodGet) {
+		allow = addMethod(allow, http.MethodHead)
+	}
+	allow = addMethod(allow, http.MethodOptions)
+	return strings.Join(allow, ", ")
+}
+
+func addMethod(methods []string, method string) []string {
+	i, found := slices.BinarySearch(methods, method)
+	if found {
+		return methods
+	}
+	return slices.Insert(methods, i, method)
+}
+
+// redirect 重定向到 u，GET 使用 301，其他方法使用 308 以保留方法和请求体
+func redirect(w http.ResponseWriter, r *http.Request, u *url.URL) {
+	code := http.StatusMovedPermanently
+	if r.Method != http.MethodGet {
+		code = http.StatusPermanentRedirect
+	}
+	http.Redirect(w, r, u.String(), code)
+}
@@ -0,0 +1,386 @@
+package httpmux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vizee/pathrouter"
+	"github.com/vizee/pathrouter/internal/testroutes"
+)
+
+func echoParams(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, name)
+		for _, p := range ParamsFromContext(r.Context()) {
+			fmt.Fprintf(w, " %s=%s", p.Key, p.Value)
+		}
+	}
+}
+
+func TestServeMux(t *testing.T) {
+	m := NewServeMux()
+	m.Handle("GET", "/users/:id", echoParams("get-user"))
+	m.Handle("DELETE", "/users/:id", echoParams("delete-user"))
+	m.Handle("GET", "/users/new", echoParams("new-user"))
+	m.HandleFunc("POST", "/users", echoParams("create-user"))
+	m.Handle("GET", "/static/*filepath", echoParams("static"))
+
+	tests := []struct {
+		method    string
+		path      string
+		wantCode  int
+		wantBody  string
+		wantAllow string
+	}{
+		{method: "GET", path: "/users/42", wantCode: 200, wantBody: "get-user id=42"},
+		{method: "DELETE", path: "/users/42", wantCode: 200, wantBody: "delete-user id=42"},
+		{method: "GET", path: "/users/new", wantCode: 200, wantBody: "new-user"},
+		{method: "POST", path: "/users", wantCode: 200, wantBody: "create-user"},
+		{method: "GET", path: "/static/css/a.css", wantCode: 200, wantBody: "static filepath=css/a.css"},
+		{method: "GET", path: "/unknown", wantCode: 404, wantBody: "404 page not found\n"},
+		{method: "HEAD", path: "/users/42", wantCode: 200, wantBody: "get-user id=42"},
+		{method: "PUT", path: "/users/42", wantCode: 405, wantBody: "Method Not Allowed\n", wantAllow: "DELETE, GET, HEAD, OPTIONS"},
+		{method: "GET", path: "/users", wantCode: 405, wantBody: "Method Not Allowed\n", wantAllow: "OPTIONS, POST"},
+		{method: "POST", path: "/static/a", wantCode: 405, wantBody: "Method Not Allowed\n", wantAllow: "GET, HEAD, OPTIONS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("status got %d, want %d", w.Code, tt.wantCode)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body got %q, want %q", w.Body.String(), tt.wantBody)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow got %q, want %q", allow, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestServeMux_Github(t *testing.T) {
+	m := NewServeMux()
+	methods := map[string][]string{}
+	for _, route := range testroutes.GitHub {
+		if err := m.Handle(route.Method, route.Path, echoParams(route.Method)); err != nil {
+			t.Fatalf("ServeMux.Handle(%q, %q) error = %v", route.Method, route.Path, err)
+		}
+		methods[route.Path] = append(methods[route.Path], route.Method)
+	}
+
+	for pattern, allowed := range methods {
+		path := strings.ReplaceAll(pattern, ":", "")
+		want := append(slices.Clone(allowed), "OPTIONS")
+		if slices.Contains(want, "GET") {
+			want = append(want, "HEAD")
+		}
+		slices.Sort(want)
+		if got := m.Allowed(path); got != strings.Join(want, ", ") {
+			t.Errorf("ServeMux.Allowed(%q) got %q, want %q", path, got, strings.Join(want, ", "))
+		}
+
+		for _, method := range []string{"GET", "POST", "PUT", "DELETE", "PATCH"} {
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+			if slices.Contains(allowed, method) {
+				if w.Code != 200 || !strings.HasPrefix(w.Body.String(), method) {
+					t.Errorf("%s %s got %d %q", method, path, w.Code, w.Body.String())
+				}
+			} else if w.Code != 405 || w.Header().Get("Allow") != strings.Join(want, ", ") {
+				t.Errorf("%s %s got %d, Allow %q", method, path, w.Code, w.Header().Get("Allow"))
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/nonexistent/path", nil))
+	if w.Code != 404 || w.Header().Get("Allow") != "" {
+		t.Errorf("GET /nonexistent/path got %d, Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+func TestServeMux_PathValue(t *testing.T) {
+	pathValues := func(keys ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s=%s;", key, r.PathValue(key))
+			}
+		}
+	}
+	m := NewServeMux()
+	m.Handle("GET", "/repos/:owner/:repo", pathValues("owner", "repo"))
+	m.Handle("GET", "/static/*filepath", pathValues("filepath"))
+	m.Handle("GET", "/all/*", pathValues("*"))
+	m.Handle("GET", "/files/:name.:ext", pathValues("name", "ext", "missing"))
+
+	std := http.NewServeMux()
+	std.Handle("GET /repos/{owner}/{repo}", pathValues("owner", "repo"))
+	std.Handle("GET /static/{filepath...}", pathValues("filepath"))
+
+	tests := []struct {
+		path    string
+		want    string
+		compare bool
+	}{
+		{path: "/repos/vizee/pathrouter", want: "owner=vizee;repo=pathrouter;", compare: true},
+		{path: "/static/css/a.css", want: "filepath=css/a.css;", compare: true},
+		{path: "/all/a/b", want: "*=a/b;"},
+		{path: "/files/a.txt", want: "name=a;ext=txt;missing=;"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Body.String() != tt.want {
+			t.Errorf("GET %s got %q, want %q", tt.path, w.Body.String(), tt.want)
+		}
+		if tt.compare {
+			sw := httptest.NewRecorder()
+			std.ServeHTTP(sw, httptest.NewRequest("GET", tt.path, nil))
+			if sw.Body.String() != w.Body.String() {
+				t.Errorf("GET %s got %q, http.ServeMux got %q", tt.path, w.Body.String(), sw.Body.String())
+			}
+		}
+	}
+}
+
+func TestServeMux_HandlePattern(t *testing.T) {
+	m := NewServeMux()
+	patterns := []string{
+		"GET /users/{id}",
+		"DELETE /users/{id}",
+		"/users/{id}/posts",
+		"GET /static/{path...}",
+		"GET /docs/",
+		"GET /docs/{$}",
+	}
+	for _, pattern := range patterns {
+		if err := m.HandlePattern(pattern, echoParams(pattern)); err != nil {
+			t.Fatalf("ServeMux.HandlePattern(%q) error = %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"example.com/", "GET /a{b}", "GET /a:b"} {
+		if err := m.HandlePattern(pattern, echoParams(pattern)); !errors.Is(err, pathrouter.ErrInvalidPath) {
+			t.Errorf("ServeMux.HandlePattern(%q) error = %v, wantErr %v", pattern, err, pathrouter.ErrInvalidPath)
+		}
+	}
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{method: "GET", path: "/users/1", wantCode: 200, wantBody: "GET /users/{id} id=1"},
+		{method: "DELETE", path: "/users/1", wantCode: 200, wantBody: "DELETE /users/{id} id=1"},
+		{method: "PUT", path: "/users/1", wantCode: 405},
+		{method: "PUT", path: "/users/1/posts", wantCode: 200, wantBody: "/users/{id}/posts id=1"},
+		{method: "GET", path: "/static/a/b", wantCode: 200, wantBody: "GET /static/{path...} path=a/b"},
+		{method: "GET", path: "/docs/", wantCode: 200, wantBody: "GET /docs/{$}"},
+		{method: "GET", path: "/docs/a", wantCode: 200, wantBody: "GET /docs/ *=a"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status got %d, want %d", tt.method, tt.path, w.Code, tt.wantCode)
+		}
+		if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s body got %q, want %q", tt.method, tt.path, w.Body.String(), tt.wantBody)
+		}
+	}
+}
+
+func TestServeMux_RedirectTrailingSlash(t *testing.T) {
+	m := &ServeMux{RedirectTrailingSlash: true}
+	m.Handle("GET", "/a/", echoParams("a"))
+	m.Handle("GET", "/b", echoParams("b"))
+	m.Handle("POST", "/c/:id", echoParams("c"))
+
+	tests := []struct {
+		method       string
+		target       string
+		wantCode     int
+		wantLocation string
+	}{
+		{method: "GET", target: "/a", wantCode: 301, wantLocation: "/a/"},
+		{method: "GET", target: "/b/?x=1", wantCode: 301, wantLocation: "/b?x=1"},
+		{method: "HEAD", target: "/b/", wantCode: 308, wantLocation: "/b"},
+		{method: "POST", target: "/c/1/", wantCode: 308, wantLocation: "/c/1"},
+		{method: "GET", target: "/a/", wantCode: 200},
+		{method: "GET", target: "/d/", wantCode: 404},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status got %d, want %d", tt.method, tt.target, w.Code, tt.wantCode)
+		}
+		if location := w.Header().Get("Location"); location != tt.wantLocation {
+			t.Errorf("%s %s Location got %q, want %q", tt.method, tt.target, location, tt.wantLocation)
+		}
+	}
+
+	m.RedirectTrailingSlash = false
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/a", nil))
+	if w.Code != 404 {
+		t.Errorf("GET /a status got %d, want %d", w.Code, 404)
+	}
+}
+
+func TestServeMux_Pattern(t *testing.T) {
+	pattern := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Pattern)
+	})
+	m := NewServeMux()
+	m.Handle("GET", "/repos/:owner/:repo", pattern)
+	m.Handle("GET", "/static/*filepath", pattern)
+	m.Handle("", "/health", pattern)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: "GET", path: "/repos/vizee/pathrouter", want: "/repos/:owner/:repo"},
+		{method: "HEAD", path: "/static/a.css", want: "/static/*filepath"},
+		{method: "POST", path: "/health", want: "/health"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s %s Pattern got %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestServeMux_RedirectCleanPath(t *testing.T) {
+	m := &ServeMux{RedirectCleanPath: true}
+	m.Handle("GET", "/static/*filepath", echoParams("static"))
+	m.Handle("POST", "/users/:id", echoParams("user"))
+
+	tests := []struct {
+		method       string
+		target       string
+		wantCode     int
+		wantLocation string
+	}{
+		{method: "GET", target: "/static/../../etc/passwd", wantCode: 301, wantLocation: "/etc/passwd"},
+		{method: "GET", target: "/static/css/../../../etc/passwd", wantCode: 301, wantLocation: "/etc/passwd"},
+		{method: "GET", target: "/static//css/./a.css?v=1", wantCode: 301, wantLocation: "/static/css/a.css?v=1"},
+		{method: "POST", target: "/a/../users/1", wantCode: 308, wantLocation: "/users/1"},
+		{method: "GET", target: "/static/css/a.css", wantCode: 200},
+		{method: "GET", target: "/etc/passwd", wantCode: 404},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tt.method, "/", nil)
+		u, _ := r.URL.Parse(tt.target)
+		r.URL = u
+		r.URL.Path = strings.Split(tt.target, "?")[0]
+		m.ServeHTTP(w, r)
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status got %d, want %d", tt.method, tt.target, w.Code, tt.wantCode)
+		}
+		if location := w.Header().Get("Location"); location != tt.wantLocation {
+			t.Errorf("%s %s Location got %q, want %q", tt.method, tt.target, location, tt.wantLocation)
+		}
+	}
+
+	// 未启用时 .. 会进入 *
+	m.RedirectCleanPath = false
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.URL.Path = "/static/../../etc/passwd"
+	m.ServeHTTP(w, r)
+	if w.Code != 200 {
+		t.Errorf("GET %s status got %d, want %d", r.URL.Path, w.Code, 200)
+	}
+}
+
+func TestServeMux_RedirectFixedPath(t *testing.T) {
+	m := &ServeMux{RedirectFixedPath: true}
+	m.Handle("GET", "/users/:id", echoParams("user"))
+	m.Handle("POST", "/Repos/", echoParams("repos"))
+
+	tests := []struct {
+		method       string
+		target       string
+		wantCode     int
+		wantLocation string
+	}{
+		{method: "GET", target: "/USERS/Bob", wantCode: 301, wantLocation: "/users/Bob"},
+		{method: "GET", target: "/a/../users//1?x=1", wantCode: 301, wantLocation: "/users/1?x=1"},
+		{method: "GET", target: "/a/../USERS//1", wantCode: 301, wantLocation: "/users/1"},
+		{method: "GET", target: "/users/../../etc/passwd", wantCode: 301, wantLocation: "/etc/passwd"},
+		{method: "HEAD", target: "/Users/1", wantCode: 308, wantLocation: "/users/1"},
+		{method: "POST", target: "/repos/./", wantCode: 308, wantLocation: "/Repos/"},
+		{method: "GET", target: "/users/1", wantCode: 200},
+		{method: "GET", target: "/repos/", wantCode: 404},
+		{method: "GET", target: "/unknown", wantCode: 404},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tt.method, "/", nil)
+		u, _ := r.URL.Parse(tt.target)
+		r.URL = u
+		r.URL.Path = strings.Split(tt.target, "?")[0]
+		m.ServeHTTP(w, r)
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status got %d, want %d", tt.method, tt.target, w.Code, tt.wantCode)
+		}
+		if location := w.Header().Get("Location"); location != tt.wantLocation {
+			t.Errorf("%s %s Location got %q, want %q", tt.method, tt.target, location, tt.wantLocation)
+		}
+	}
+}
+
+func TestServeMux_Handlers(t *testing.T) {
+	m := &ServeMux{
+		NotFound: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}),
+		MethodNotAllowed: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}),
+	}
+	m.Handle("GET", "/a", echoParams("a"))
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/b", nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("NotFound status got %d, want %d", w.Code, http.StatusTeapot)
+	}
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("POST", "/a", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("MethodNotAllowed status got %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestServeMux_Handle(t *testing.T) {
+	m := NewServeMux()
+	if err := m.Handle("GET", "/a/:b", echoParams("a")); err != nil {
+		t.Fatalf("ServeMux.Handle() error = %v", err)
+	}
+	if err := m.Handle("GET", "/a/:b", echoParams("a")); !errors.Is(err, pathrouter.ErrDuplicate) {
+		t.Fatalf("ServeMux.Handle() error = %v, wantErr %v", err, pathrouter.ErrDuplicate)
+	}
+	if err := m.Handle("GET", "/a/:c", echoParams("a")); !errors.Is(err, pathrouter.ErrConflict) {
+		t.Fatalf("ServeMux.Handle() error = %v, wantErr %v", err, pathrouter.ErrConflict)
+	}
+	if err := m.Handle("POST", "/a/:c", echoParams("a")); err != nil {
+		t.Fatalf("ServeMux.Handle() error = %v", err)
+	}
+	if _, ps, ok := m.Lookup("POST", "/a/x"); !ok || len(ps) != 1 || ps[0].Key != "c" {
+		t.Fatalf("ServeMux.Lookup() got %v, %v", ps, ok)
+	}
+}
@@ -0,0 +1,48 @@
+package pathrouter
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// Mount 将 sub 的全部路由以 prefix 为前缀添加到 r，prefix 与 sub 的 pattern 直接连接，
+// 例如 /orgs/:org 与 /teams 连接为 /orgs/:org/teams，sub 的路由名称关联到连接后的 pattern
+// 无论 r.Duplicate 是什么，已经存在的 pattern 都返回 ErrDuplicate，
+// 任一路由或名称失败时 r 保持不变，返回全部错误
+// sub 的 NormalizePath 等选项不会被应用，之后对 sub 的修改也不会影响 r
+func (r *Router[T]) Mount(prefix string, sub *Router[T]) error {
+	var routes []Route[T]
+	sub.Walk(func(pattern string, value T) bool {
+		routes = append(routes, Route[T]{Pattern: prefix + pattern, Value: value})
+		return true
+	})
+
+	cp := *r
+	cp.Duplicate = DuplicateError
+	var errs []error
+	err := cp.AddAll(routes)
+	cp.Duplicate = r.Duplicate
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(sub.names)) {
+		path := prefix + sub.names[name]
+		err := cp.checkName(name, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cp.setName(name, path)
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	*r = cp
+	return nil
+}
+
+// Merge 将 other 的全部路由添加到 r，与 Mount("", other) 相同
+func (r *Router[T]) Merge(other *Router[T]) error {
+	return r.Mount("", other)
+}
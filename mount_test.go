@@ -0,0 +1,137 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouter_Mount(t *testing.T) {
+	sub := &Router[int]{}
+	sub.AddNamed("team", "/teams/:team", 1)
+	sub.Add("/members", 2)
+	sub.Add("/files/*path", 3)
+	sub.Add("", 4)
+
+	r := &Router[int]{Duplicate: DuplicateError}
+	r.Add("/orgs", 10)
+	err := r.Mount("/orgs/:org", sub)
+	if err != nil {
+		t.Fatalf("Router.Mount() error = %v", err)
+	}
+	err = r.Mount("/api", buildRouter([]string{"/members"}))
+	if err != nil {
+		t.Fatalf("Router.Mount() error = %v", err)
+	}
+	// 名称已经关联到其他 pattern
+	if err := r.Mount("/v2", sub); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Router.Mount() error = %v, want %v", err, ErrDuplicate)
+	}
+
+	tests := []struct {
+		path    string
+		wantRes MatchResult[int]
+	}{
+		{path: "/orgs", wantRes: MatchResult[int]{Value: 10, Pattern: "/orgs"}},
+		{path: "/orgs/a", wantRes: MatchResult[int]{Params: Params{{Key: "org", Value: "a"}}, Value: 4, Pattern: "/orgs/:org"}},
+		{path: "/orgs/a/teams/b", wantRes: MatchResult[int]{Params: Params{{Key: "org", Value: "a"}, {Key: "team", Value: "b"}}, Value: 1, Pattern: "/orgs/:org/teams/:team"}},
+		{path: "/orgs/a/files/x/y", wantRes: MatchResult[int]{Params: Params{{Key: "org", Value: "a"}, {Key: "path", Value: "x/y"}}, Value: 3, Pattern: "/orgs/:org/files/*path"}},
+		{path: "/api/members", wantRes: MatchResult[int]{Value: 100, Pattern: "/api/members"}},
+	}
+	for _, tt := range tests {
+		var res MatchResult[int]
+		if !r.Match(tt.path, &res) {
+			t.Errorf("Router.Match(%q) = false", tt.path)
+			continue
+		}
+		if !reflect.DeepEqual(res, tt.wantRes) {
+			t.Errorf("Router.Match(%q) got %+v, want %+v", tt.path, res, tt.wantRes)
+		}
+	}
+
+	if pattern, _ := r.Pattern("team"); pattern != "/orgs/:org/teams/:team" {
+		t.Errorf("Router.Pattern() got %q", pattern)
+	}
+	if path, err := r.Build("team", Params{{Key: "org", Value: "a"}, {Key: "team", Value: "b"}}); err != nil || path != "/orgs/a/teams/b" {
+		t.Errorf("Router.Build() got %q, %v", path, err)
+	}
+}
+
+func TestRouter_MountConflict(t *testing.T) {
+	sub := &Router[int]{}
+	sub.AddNamed("user", "/users/:name", 1)
+	sub.Add("/about", 2)
+	sub.Add(":x", 3)
+
+	tests := []struct {
+		name     string
+		prefix   string
+		wantErrs []error
+	}{
+		{name: "conflict", prefix: "/api", wantErrs: []error{ErrConflict, ErrDuplicate}},
+		{name: "invalid", prefix: "/p/:p", wantErrs: []error{ErrInvalidPath}},
+		{name: "catch-all", prefix: "/c/*", wantErrs: []error{ErrInvalidPath}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Router[int]{Duplicate: DuplicateError}
+			r.AddNamed("user", "/api/users/:id", 10)
+			r.Add("/api/about", 11)
+			before := *r
+			err := r.Mount(tt.prefix, sub)
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("Router.Mount(%q) error = %v, want %v", tt.prefix, err, want)
+				}
+			}
+			if !reflect.DeepEqual(*r, before) {
+				t.Errorf("Router.Mount(%q) modified router on failure", tt.prefix)
+			}
+		})
+	}
+}
+
+func TestRouter_Merge(t *testing.T) {
+	a := buildRouter([]string{"/a", "/users/:id"})
+	b := buildRouter([]string{"/b", "/users/:id/posts"})
+	err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Router.Merge() error = %v", err)
+	}
+	var got []string
+	for pattern := range a.All() {
+		got = append(got, pattern)
+	}
+	want := []string{"/a", "/b", "/users/:id", "/users/:id/posts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Router.All() after Merge got %q, want %q", got, want)
+	}
+
+	err = a.Merge(buildRouter([]string{"/users/:name"}))
+	var ce *ConflictError
+	if !errors.As(err, &ce) || ce.Existing != "/users/:id" {
+		t.Errorf("Router.Merge() error = %v, want conflict with %q", err, "/users/:id")
+	}
+}
+
+func TestRouter_MergeDuplicate(t *testing.T) {
+	for _, dup := range []DuplicatePolicy{DuplicateReplace, DuplicateError, DuplicateIgnore} {
+		a := &Router[int]{Duplicate: dup}
+		a.Add("/a", 1)
+		b := &Router[int]{}
+		b.Add("/a", 2)
+		b.Add("/b", 3)
+		before := *a
+		err := a.Merge(b)
+		if !errors.Is(err, ErrDuplicate) {
+			t.Errorf("Router.Merge() with policy %d error = %v, want %v", dup, err, ErrDuplicate)
+		}
+		if !reflect.DeepEqual(*a, before) {
+			t.Errorf("Router.Merge() with policy %d modified router on failure", dup)
+		}
+		var res MatchResult[int]
+		if !a.Match("/a", &res) || res.Value != 1 {
+			t.Errorf("Router.Match(%q) after Merge with policy %d got %v", "/a", dup, res.Value)
+		}
+	}
+}
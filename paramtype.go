@@ -0,0 +1,220 @@
+package pathrouter
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ParamType 将 param 的值转换为对应类型，值不合法时返回 false
+type ParamType func(value string) (any, bool)
+
+type paramType struct {
+	name string
+	conv ParamType
+}
+
+var (
+	paramTypesMu sync.RWMutex
+	paramTypes   = map[string]*paramType{}
+)
+
+func init() {
+	RegisterParamType("int", func(value string) (any, bool) {
+		v, err := strconv.ParseInt(value, 10, 64)
+		return v, err == nil
+	})
+	RegisterParamType("uint", func(value string) (any, bool) {
+		v, err := strconv.ParseUint(value, 10, 64)
+		return v, err == nil
+	})
+	RegisterParamType("hex", func(value string) (any, bool) {
+		v, err := strconv.ParseUint(value, 16, 64)
+		return v, err == nil
+	})
+	RegisterParamType("bool", func(value string) (any, bool) {
+		v, err := strconv.ParseBool(value)
+		return v, err == nil
+	})
+	RegisterParamType("date", func(value string) (any, bool) {
+		v, err := time.Parse(time.DateOnly, value)
+		return v, err == nil
+	})
+	RegisterParamType("uuid", func(value string) (any, bool) {
+		return parseUUID(value)
+	})
+}
+
+// RegisterParamType 注册 :name<type> 中可以使用的类型，同名类型会被替换
+// 已经添加的路由不受影响
+func RegisterParamType(name string, conv ParamType) {
+	if name == "" || conv == nil {
+		panic("pathrouter: invalid param type")
+	}
+	paramTypesMu.Lock()
+	paramTypes[name] = &paramType{name: name, conv: conv}
+	paramTypesMu.Unlock()
+}
+
+func lookupParamType(name string) *paramType {
+	paramTypesMu.RLock()
+	typ := paramTypes[name]
+	paramTypesMu.RUnlock()
+	return typ
+}
+
+// UUID 是 uuid 类型 param 转换后的值
+type UUID [16]byte
+
+func (u UUID) String() string {
+	const digits = "0123456789abcdef"
+	var buf [36]byte
+	j := 0
+	for i, b := range u {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			buf[j] = '-'
+			j++
+		}
+		buf[j] = digits[b>>4]
+		buf[j+1] = digits[b&0xf]
+		j += 2
+	}
+	return string(buf[:])
+}
+
+func parseUUID(s string) (UUID, bool) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, false
+	}
+	j := 0
+	for i := 0; i < len(s); i += 2 {
+		if s[i] == '-' {
+			i--
+			continue
+		}
+		hi, ok1 := unhex(s[i])
+		lo, ok2 := unhex(s[i+1])
+		if !ok1 || !ok2 {
+			return u, false
+		}
+		u[j] = hi<<4 | lo
+		j++
+	}
+	return u, true
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// Int 返回 int 类型 param 的值，未标注类型时解析 Value
+func (p *Param) Int() (int64, bool) {
+	if v, ok := p.Typed.(int64); ok {
+		return v, true
+	}
+	v, err := strconv.ParseInt(p.Value, 10, 64)
+	return v, err == nil
+}
+
+// Uint 返回 uint 或 hex 类型 param 的值，未标注类型时按照十进制解析 Value，
+// 因此未标注 hex 类型的十六进制值无法获取
+func (p *Param) Uint() (uint64, bool) {
+	if v, ok := p.Typed.(uint64); ok {
+		return v, true
+	}
+	v, err := strconv.ParseUint(p.Value, 10, 64)
+	return v, err == nil
+}
+
+// Bool 返回 bool 类型 param 的值，未标注类型时解析 Value
+func (p *Param) Bool() (bool, bool) {
+	if v, ok := p.Typed.(bool); ok {
+		return v, true
+	}
+	v, err := strconv.ParseBool(p.Value)
+	return v, err == nil
+}
+
+// Time 返回 date 类型 param 的值，未标注类型时解析 Value
+func (p *Param) Time() (time.Time, bool) {
+	if v, ok := p.Typed.(time.Time); ok {
+		return v, true
+	}
+	v, err := time.Parse(time.DateOnly, p.Value)
+	return v, err == nil
+}
+
+// UUID 返回 uuid 类型 param 的值，未标注类型时解析 Value
+func (p *Param) UUID() (UUID, bool) {
+	if v, ok := p.Typed.(UUID); ok {
+		return v, true
+	}
+	return parseUUID(p.Value)
+}
+
+func (ps Params) lookup(key string) *Param {
+	for i := range ps {
+		if ps[i].Key == key {
+			return &ps[i]
+		}
+	}
+	return nil
+}
+
+// Typed 返回 key 对应 param 转换后的值
+func (ps Params) Typed(key string) (any, bool) {
+	p := ps.lookup(key)
+	if p == nil || p.Typed == nil {
+		return nil, false
+	}
+	return p.Typed, true
+}
+
+func (ps Params) Int(key string) (int64, bool) {
+	p := ps.lookup(key)
+	if p == nil {
+		return 0, false
+	}
+	return p.Int()
+}
+
+func (ps Params) Uint(key string) (uint64, bool) {
+	p := ps.lookup(key)
+	if p == nil {
+		return 0, false
+	}
+	return p.Uint()
+}
+
+func (ps Params) Bool(key string) (bool, bool) {
+	p := ps.lookup(key)
+	if p == nil {
+		return false, false
+	}
+	return p.Bool()
+}
+
+func (ps Params) Time(key string) (time.Time, bool) {
+	p := ps.lookup(key)
+	if p == nil {
+		return time.Time{}, false
+	}
+	return p.Time()
+}
+
+func (ps Params) UUID(key string) (UUID, bool) {
+	p := ps.lookup(key)
+	if p == nil {
+		return UUID{}, false
+	}
+	return p.UUID()
+}
@@ -0,0 +1,145 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_MatchTyped(t *testing.T) {
+	routes := []string{
+		"/int/:v<int>",
+		"/uint/:v<uint>",
+		"/hex/:v<hex>",
+		"/bool/:v<bool>",
+		"/date/:v<date>",
+		"/uuid/:v<uuid>",
+		"/code/:v<int>{[0-9]{3}}",
+	}
+	tests := []struct {
+		name  string
+		path  string
+		want  bool
+		typed any
+		value int
+	}{
+		{name: "int", path: "/int/-42", want: true, typed: int64(-42), value: 100},
+		{name: "int-fail", path: "/int/4x", want: false},
+		{name: "uint", path: "/uint/42", want: true, typed: uint64(42), value: 101},
+		{name: "uint-fail", path: "/uint/-42", want: false},
+		{name: "hex", path: "/hex/ff", want: true, typed: uint64(255), value: 102},
+		{name: "hex-fail", path: "/hex/fg", want: false},
+		{name: "bool", path: "/bool/true", want: true, typed: true, value: 103},
+		{name: "bool-fail", path: "/bool/yes", want: false},
+		{name: "date", path: "/date/2024-02-29", want: true, typed: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), value: 104},
+		{name: "date-fail", path: "/date/2023-02-29", want: false},
+		{name: "uuid", path: "/uuid/123e4567-e89b-12d3-a456-426614174000", want: true, typed: UUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, value: 105},
+		{name: "uuid-fail", path: "/uuid/123e4567-e89b-12d3-a456-42661417400g", want: false},
+		{name: "regexp", path: "/code/404", want: true, typed: int64(404), value: 106},
+		{name: "regexp-fail", path: "/code/4040", want: false},
+	}
+	r := buildRouter(routes)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res MatchResult[int]
+			if got := r.Match(tt.path, &res); got != tt.want {
+				t.Fatalf("Router.Match() = %v, want %v", got, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			if res.Value != tt.value {
+				t.Errorf("Value got %v, want %v", res.Value, tt.value)
+			}
+			if got, _ := res.Params.Typed("v"); !reflect.DeepEqual(got, tt.typed) {
+				t.Errorf("Params.Typed() got %#v, want %#v", got, tt.typed)
+			}
+		})
+	}
+}
+
+func TestRouter_AddTyped(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   string
+		wantErr error
+	}{
+		{name: "int", route: "/:id<int>"},
+		{name: "int-regexp", route: "/:id<int>{[0-9]+}/a"},
+		{name: "unknown", route: "/:id<float>", wantErr: ErrInvalidPath},
+		{name: "unclosed", route: "/:id<int", wantErr: ErrInvalidPath},
+		{name: "no-name", route: "/:<int>", wantErr: ErrInvalidPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Router[int]{}
+			err := r.Add(tt.route, 100)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Router.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterParamType(t *testing.T) {
+	RegisterParamType("upper", func(value string) (any, bool) {
+		return value, value == strings.ToUpper(value)
+	})
+	r := buildRouter([]string{"/:code<upper>"})
+	var res MatchResult[int]
+	if !r.Match("/ABC", &res) {
+		t.Fatal("Router.Match() = false, want true")
+	}
+	res = MatchResult[int]{}
+	if r.Match("/abc", &res) {
+		t.Fatal("Router.Match() = true, want false")
+	}
+}
+
+func TestParam_Accessors(t *testing.T) {
+	ps := Params{
+		{Key: "a", Value: "42"},
+		{Key: "b", Value: "ff", Typed: uint64(255)},
+		{Key: "c", Value: "x"},
+		{Key: "d", Value: "true", Typed: true},
+		{Key: "e", Value: "2024-02-29"},
+		{Key: "f", Value: "123e4567-e89b-12d3-a456-426614174000"},
+		{Key: "g", Value: "ff"},
+	}
+	if v, ok := ps.Int("a"); !ok || v != 42 {
+		t.Errorf("Params.Int() got %v, %v", v, ok)
+	}
+	if v, ok := ps.Uint("b"); !ok || v != 255 {
+		t.Errorf("Params.Uint() got %v, %v", v, ok)
+	}
+	if _, ok := ps.Int("c"); ok {
+		t.Errorf("Params.Int() got ok for %q", "x")
+	}
+	if _, ok := ps.Uint("g"); ok {
+		t.Errorf("Params.Uint() got ok for untyped %q", "ff")
+	}
+	if v, ok := ps.Bool("d"); !ok || !v {
+		t.Errorf("Params.Bool() got %v, %v", v, ok)
+	}
+	if _, ok := ps.Bool("c"); ok {
+		t.Errorf("Params.Bool() got ok for %q", "x")
+	}
+	if v, ok := ps.Time("e"); !ok || !v.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Params.Time() got %v, %v", v, ok)
+	}
+	if v, ok := ps.UUID("f"); !ok || v.String() != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("Params.UUID() got %v, %v", v, ok)
+	}
+	if _, ok := ps.UUID("missing"); ok {
+		t.Errorf("Params.UUID() got ok for missing key")
+	}
+	if _, ok := ps.Typed("a"); ok {
+		t.Errorf("Params.Typed() got ok for untyped param")
+	}
+	u := UUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	if got := u.String(); got != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("UUID.String() got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package pathrouter
+
+import "strings"
+
+// SegmentKind 是 pattern 中片段的类型
+type SegmentKind uint8
+
+const (
+	// SegmentStatic 是静态文本
+	SegmentStatic SegmentKind = iota
+	// SegmentParam 是 :name，可以带有类型和正则约束
+	SegmentParam
+	// SegmentCatchAll 是 * 或 *name，匹配剩余的全部路径
+	SegmentCatchAll
+)
+
+func (k SegmentKind) String() string {
+	switch k {
+	case SegmentStatic:
+		return "static"
+	case SegmentParam:
+		return "param"
+	case SegmentCatchAll:
+		return "catch-all"
+	}
+	return "unknown"
+}
+
+// Segment 是 pattern 中的一个片段
+type Segment struct {
+	Kind SegmentKind
+	// Text 是片段在 pattern 中的原文，静态片段的 String 返回 Text
+	Text string
+	// Name 是 param 或 * 的名称，未命名的 * 为 "*"，与匹配时的 Param.Key 相同
+	Name string
+	// Type 是 param 的类型名称
+	Type string
+	// Constraint 是 param 的正则约束，不包含 {}，为空表示没有约束，空的 {} 在解析时被拒绝
+	Constraint string
+}
+
+// String 根据 Kind 和各字段生成片段的文本
+func (s Segment) String() string {
+	switch s.Kind {
+	case SegmentParam:
+		text := ":" + s.Name
+		if s.Type != "" {
+			text += "<" + s.Type + ">"
+		}
+		if s.Constraint != "" {
+			text += "{" + s.Constraint + "}"
+		}
+		return text
+	case SegmentCatchAll:
+		if s.Name == "*" {
+			return "*"
+		}
+		return "*" + s.Name
+	}
+	return s.Text
+}
+
+// Pattern 是解析后的路由，片段按照在 pattern 中的顺序排列
+type Pattern []Segment
+
+// ParsePattern 按照 Router.Add 的规则解析 pattern，但不修改任何 Router
+// 错误与 Router.Add 相同，是 *PatternError
+func ParsePattern(pattern string) (Pattern, error) {
+	segs, err := parsePath[struct{}](pattern)
+	if err != nil {
+		return nil, err
+	}
+	p := make(Pattern, 0, len(segs))
+	for _, n := range segs {
+		seg := Segment{Text: n.path}
+		switch n.kind {
+		case paramKind:
+			seg.Kind = SegmentParam
+			seg.Name = n.key
+			if n.typ != nil {
+				seg.Type = n.typ.name
+			}
+			if n.re != nil {
+				seg.Constraint = n.path[strings.IndexByte(n.path, '{')+1 : len(n.path)-1]
+			}
+		case trailingKind:
+			seg.Kind = SegmentCatchAll
+			seg.Name = n.key
+		}
+		p = append(p, seg)
+	}
+	return p, nil
+}
+
+// String 返回 pattern 的文本，解析得到的 Pattern 返回原来的 pattern
+func (p Pattern) String() string {
+	var sb strings.Builder
+	for _, seg := range p {
+		sb.WriteString(seg.String())
+	}
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    Pattern
+		wantErr error
+	}{
+		{pattern: "", want: Pattern{}},
+		{pattern: "/users", want: Pattern{{Kind: SegmentStatic, Text: "/users"}}},
+		{pattern: "/users/:id", want: Pattern{{Kind: SegmentStatic, Text: "/users/"}, {Kind: SegmentParam, Text: ":id", Name: "id"}}},
+		{pattern: "/files/:name.:ext", want: Pattern{{Kind: SegmentStatic, Text: "/files/"}, {Kind: SegmentParam, Text: ":name", Name: "name"}, {Kind: SegmentStatic, Text: "."}, {Kind: SegmentParam, Text: ":ext", Name: "ext"}}},
+		{pattern: "/n/:id<int>{[0-9]{1,3}}/x", want: Pattern{{Kind: SegmentStatic, Text: "/n/"}, {Kind: SegmentParam, Text: ":id<int>{[0-9]{1,3}}", Name: "id", Type: "int", Constraint: "[0-9]{1,3}"}, {Kind: SegmentStatic, Text: "/x"}}},
+		{pattern: "/u/:uid<uuid>", want: Pattern{{Kind: SegmentStatic, Text: "/u/"}, {Kind: SegmentParam, Text: ":uid<uuid>", Name: "uid", Type: "uuid"}}},
+		{pattern: "/static/*filepath", want: Pattern{{Kind: SegmentStatic, Text: "/static/"}, {Kind: SegmentCatchAll, Text: "*filepath", Name: "filepath"}}},
+		{pattern: "/all/*", want: Pattern{{Kind: SegmentStatic, Text: "/all/"}, {Kind: SegmentCatchAll, Text: "*", Name: "*"}}},
+		{pattern: "/users/:", wantErr: ErrInvalidPath},
+		{pattern: "/a/*x/b", wantErr: ErrInvalidPath},
+		{pattern: "/:a:b", wantErr: ErrInvalidPath},
+		{pattern: "/:id{[0-9+}", wantErr: ErrInvalidPath},
+		{pattern: "/:a{}", wantErr: ErrInvalidPath},
+		{pattern: "/:a<int>{}", wantErr: ErrInvalidPath},
+		{pattern: "/:id<float>", wantErr: ErrInvalidPath},
+		{pattern: "/a/*rest{x}", wantErr: ErrInvalidPath},
+	}
+	for _, tt := range tests {
+		got, err := ParsePattern(tt.pattern)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParsePattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePattern(%q) got %+v, want %+v", tt.pattern, got, tt.want)
+		}
+		if s := got.String(); s != tt.pattern {
+			t.Errorf("Pattern.String() got %q, want %q", s, tt.pattern)
+		}
+	}
+}
+
+func TestPattern_String(t *testing.T) {
+	p := Pattern{
+		{Kind: SegmentStatic, Text: "/repos/"},
+		{Kind: SegmentParam, Name: "owner"},
+		{Kind: SegmentStatic, Text: "/"},
+		{Kind: SegmentParam, Name: "id", Type: "int", Constraint: "[0-9]+"},
+		{Kind: SegmentStatic, Text: "/raw/"},
+		{Kind: SegmentCatchAll, Name: "path"},
+	}
+	want := "/repos/:owner/:id<int>{[0-9]+}/raw/*path"
+	if s := p.String(); s != want {
+		t.Fatalf("Pattern.String() got %q, want %q", s, want)
+	}
+	parsed, err := ParsePattern(want)
+	if err != nil {
+		t.Fatalf("ParsePattern(%q) error = %v", want, err)
+	}
+	if parsed.String() != want {
+		t.Errorf("ParsePattern(%q).String() got %q", want, parsed.String())
+	}
+}
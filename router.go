@@ -2,17 +2,38 @@ package pathrouter
 
 import (
 	"errors"
+	"fmt"
+	"iter"
+	"regexp"
+	"slices"
 	"strings"
 )
 
 var (
 	ErrConflict    = errors.New("Path conflict")
 	ErrInvalidPath = errors.New("Invalid path")
+	ErrDuplicate   = errors.New("Duplicate path")
+	ErrNotFound    = errors.New("Path not found")
+	ErrBuild       = errors.New("Build path failed")
+)
+
+// DuplicatePolicy 决定 Add 遇到已存在的路由时的行为
+type DuplicatePolicy uint8
+
+const (
+	// DuplicateReplace 替换已存在的值
+	DuplicateReplace DuplicatePolicy = iota
+	// DuplicateError 返回 ErrDuplicate
+	DuplicateError
+	// DuplicateIgnore 保留已存在的值
+	DuplicateIgnore
 )
 
 type Param struct {
 	Key   string
 	Value string
+	// Typed 是带类型标注的 param 转换后的值
+	Typed any
 }
 
 type Params []Param
@@ -29,147 +50,215 @@ func (ps Params) Get(key string) (string, bool) {
 type MatchResult[T any] struct {
 	Params Params
 	Value  T
+	// Pattern 是匹配到的路由添加时的 pattern，例如 /repos/:owner/:repo
+	Pattern string
+	// TSR 表示匹配失败，但添加或删除末尾的 / 后能够匹配
+	TSR bool
+	// NormalizedPath 是 NormalizePath 修改后用于匹配的路径，路径未被修改时为空
+	NormalizedPath string
 }
 
+// Router 的修改不会改变已有的节点，而是复制被修改路径上的节点，
+// 因此复制的 Router 值是互不影响的快照
 type Router[T any] struct {
+	// Duplicate 是 Add 的重复路由策略，默认替换
+	Duplicate DuplicatePolicy
+	// NormalizePath 为 true 时，Match 先使用 CleanPath 规范化路径
+	NormalizePath bool
+	// CaseFold 不为 FoldNone 时，Match 在精确匹配失败后忽略静态文本的大小写重新匹配
+	CaseFold CaseFold
+
 	root *node[T]
+	// 路由名称到 pattern 的映射，修改时复制
+	names map[string]string
 }
 
+// Match 匹配 path，同一位置优先尝试静态子节点，其次 param，最后 *，
+// 后续路径匹配失败时回退尝试下一种
+// param 之后的静态文本从最短的 param 值开始尝试，最后尝试 param 捕获到 / 之前的全部文本
 func (r *Router[T]) Match(path string, res *MatchResult[T]) bool {
+	if r.NormalizePath {
+		res.NormalizedPath = ""
+		if clean := CleanPath(path); clean != path {
+			res.NormalizedPath = clean
+			path = clean
+		}
+	}
 	if r.root == nil {
+		res.Pattern = ""
+		res.TSR = false
 		return false
 	}
 
-	cur := r.root
-	for {
-		switch cur.kind {
-		case staticKind:
-			if !strings.HasPrefix(path, cur.path) {
-				return false
-			}
-			path = path[len(cur.path):]
-		case paramKind:
-			// 根节点是 param 或父节点标记 wildChild
-			// param 要求 path 非空，捕获到下一个 / 之前
-			if path == "" {
-				return false
-			}
-			slash := strings.IndexByte(path, '/')
-			if slash < 0 {
-				slash = len(path)
-			}
-			res.Params = append(res.Params, Param{Key: cur.path[1:], Value: path[:slash]})
-			path = path[slash:]
-		case trailingKind:
-			res.Params = append(res.Params, Param{Key: "*", Value: path})
-			path = ""
-		}
-
-		// 如果 path 完成匹配，当前节点不是终止节点，考虑子节点存在 *
-		if path == "" && (cur.end || !cur.wildChild) {
-			break
-		}
-
-		if cur.wildChild {
-			cur = cur.children[0]
-		} else {
-			found := false
-			start := path[0]
-			for i := 0; i < len(cur.indices); i++ {
-				if cur.indices[i] == start {
-					cur = cur.children[i]
-					found = true
-					break
-				}
-			}
-			if !found {
-				break
-			}
-		}
+	n := r.lookup(path, res)
+	if n == nil {
+		res.Pattern = ""
+		res.TSR = r.tsr(path, res)
+		return false
 	}
+	res.Value = n.value
+	res.Pattern = n.pattern
+	res.TSR = false
+	return true
+}
 
-	if path == "" && cur.end {
-		res.Value = cur.value
-		return true
+func (r *Router[T]) lookup(path string, res *MatchResult[T]) *node[T] {
+	n := r.root.lookup(path, res, nil)
+	if n == nil && r.CaseFold != FoldNone {
+		n = r.root.foldLookup(path, res, r.CaseFold, nil)
 	}
-
-	return false
+	return n
 }
 
-func (r *Router[T]) Add(path string, value T) error {
-	if r.root == nil {
-		// 如果是一颗空树，直接设置根节点
-		root := &node[T]{}
-		err := root.init(path, value)
-		if err != nil {
-			return err
-		}
-		r.root = root
+// tsr 检查添加或删除 path 末尾的 / 后能否匹配，只在匹配失败时调用
+func (r *Router[T]) tsr(path string, res *MatchResult[T]) bool {
+	mark := len(res.Params)
+	var n *node[T]
+	if strings.HasSuffix(path, "/") {
+		n = r.lookup(path[:len(path)-1], res)
+	} else {
+		n = r.lookup(path+"/", res)
 	}
+	res.Params = res.Params[:mark]
+	return n != nil
+}
 
-	var orig *node[T]
+// Add 添加路由，按照 Duplicate 处理已存在的路由
+func (r *Router[T]) Add(path string, value T) error {
+	return r.add(path, value, r.Duplicate)
+}
 
-	cur := r.root
-	for {
-		l := commonPrefixLength(cur.path, path)
-		if l < len(cur.path) {
-			// 分裂节点
-			if cur.kind != staticKind {
-				// 只有静态节点支持分裂
-				return ErrConflict
-			}
+// Insert 添加路由，路由已存在时返回 ErrDuplicate
+func (r *Router[T]) Insert(path string, value T) error {
+	return r.add(path, value, DuplicateError)
+}
 
-			child := &node[T]{
-				kind:      cur.kind,
-				end:       cur.end,
-				wildChild: cur.wildChild,
-				path:      cur.path[l:],
-				indices:   cur.indices,
-				children:  cur.children,
-				value:     cur.value,
-			}
+// Upsert 添加路由，路由已存在时替换值
+func (r *Router[T]) Upsert(path string, value T) error {
+	return r.add(path, value, DuplicateReplace)
+}
 
-			orig = cur
-			cur = &node[T]{
-				kind:      cur.kind,
-				end:       false,
-				wildChild: false,
-				path:      orig.path[:l],
-				indices:   child.path[:1],
-				children:  []*node[T]{child},
-			}
-		}
+// Update 替换已存在路由的值，路由不存在时返回 ErrNotFound
+func (r *Router[T]) Update(path string, value T) error {
+	segs, err := parsePath[T](path)
+	if err != nil {
+		return err
+	}
+	stack := r.find(segs)
+	if stack == nil || !stack[len(stack)-1].end {
+		return fmt.Errorf("%w: %q", ErrNotFound, path)
+	}
+	stack = clonePath(stack)
+	stack[len(stack)-1].set(value)
+	r.root = stack[0]
+	return nil
+}
 
-		path = path[l:]
+func (r *Router[T]) add(path string, value T, policy DuplicatePolicy) error {
+	segs, err := parsePath[T](path)
+	if err != nil {
+		return err
+	}
 
-		// 路径完全匹配
-		if path == "" {
-			break
-		}
+	// 修改只作用于复制的节点，失败时原有的树保持不变
+	var root *node[T]
+	if r.root == nil {
+		// 根节点是一个空的静态节点
+		root = &node[T]{}
+	} else {
+		root = r.root.clone()
+	}
 
-		found := false
-		start := path[0]
-		for i := 0; i < len(cur.indices); i++ {
-			if cur.indices[i] == start {
-				cur = cur.children[i]
-				found = true
-				break
+	n := root
+	offset := 0
+	for _, seg := range segs {
+		n, err = n.insert(seg)
+		if err != nil {
+			if ce, ok := err.(*ConflictError); ok {
+				ce.Pattern = path
+				ce.Offset = offset
 			}
+			return err
 		}
-		if !found {
-			break
+		offset += len(seg.path)
+	}
+	if n.end {
+		switch policy {
+		case DuplicateError:
+			return fmt.Errorf("%w: %q", ErrDuplicate, path)
+		case DuplicateIgnore:
+			return nil
 		}
 	}
+	n.set(value)
+	n.pattern = path
+	r.root = root
+	return nil
+}
 
-	err := cur.addSubPath(path, value)
+// find 返回从根节点到 segs 对应节点经过的节点，不存在时返回 nil
+func (r *Router[T]) find(segs []*node[T]) []*node[T] {
+	if r.root == nil {
+		return nil
+	}
+	stack := []*node[T]{r.root}
+	for _, seg := range segs {
+		stack = stack[len(stack)-1].find(seg, stack)
+		if stack == nil {
+			return nil
+		}
+	}
+	return stack
+}
+
+// Remove 删除 path 对应的路由，并重新压缩节点，返回路由是否存在
+func (r *Router[T]) Remove(path string) bool {
+	segs, err := parsePath[T](path)
 	if err != nil {
-		return err
+		return false
+	}
+	stack := r.find(segs)
+	if stack == nil {
+		return false
+	}
+	if !stack[len(stack)-1].end {
+		return false
+	}
+	stack = clonePath(stack)
+	n := stack[len(stack)-1]
+	var zero T
+	n.end = false
+	n.value = zero
+	n.pattern = ""
+
+	for i := len(stack) - 1; i > 0; i-- {
+		n := stack[i]
+		if !n.end && len(n.children) == 0 {
+			stack[i-1].removeChild(n)
+			continue
+		}
+		n.merge()
+		break
+	}
+	r.root = stack[0]
+	if !r.root.end && len(r.root.children) == 0 {
+		r.root = nil
 	}
+	r.removeNames(path)
+	return true
+}
 
-	if orig != nil {
-		*orig = *cur
+// Walk 按照确定的顺序遍历全部路由，fn 返回 false 时停止
+// 同一位置的静态子节点按首字节排序，随后是 param 和 *，与匹配的优先级一致
+func (r *Router[T]) Walk(fn func(pattern string, value T) bool) {
+	if r.root != nil {
+		r.root.walk("", fn)
 	}
-	return nil
+}
+
+// All 返回全部路由的迭代器，顺序与 Walk 相同
+func (r *Router[T]) All() iter.Seq2[string, T] {
+	return r.Walk
 }
 
 const (
@@ -186,6 +275,33 @@ type node[T any] struct {
 	indices   string
 	children  []*node[T]
 	value     T
+	// 终止节点的 pattern，匹配时直接返回，避免重新拼接
+	pattern string
+	// param 和 * 的名称
+	key string
+	// param 的类型和正则约束
+	typ *paramType
+	re  *regexp.Regexp
+}
+
+// clone 复制节点和子节点列表，子节点本身仍然共享
+func (n *node[T]) clone() *node[T] {
+	c := *n
+	c.children = slices.Clone(n.children)
+	return &c
+}
+
+// clonePath 复制从根节点开始的一组节点，并将复制的节点链接起来
+func clonePath[T any](stack []*node[T]) []*node[T] {
+	path := make([]*node[T], len(stack))
+	for i, n := range stack {
+		path[i] = n.clone()
+		if i > 0 {
+			parent := path[i-1]
+			parent.children[slices.Index(parent.children, n)] = path[i]
+		}
+	}
+	return path
 }
 
 func (n *node[T]) set(value T) {
@@ -193,75 +309,372 @@ func (n *node[T]) set(value T) {
 	n.end = true
 }
 
-func (n *node[T]) addChild(child *node[T]) error {
-	switch n.kind {
-	case trailingKind:
-		return ErrInvalidPath
-	case paramKind:
-		// 只有 static 下可以跟随 param 和 *
-		if child.kind != staticKind {
-			return ErrInvalidPath
-		} else if child.path[0] != '/' {
-			// param 的 child 必须是 / 开头，否则说明是一个 param 被分裂
-			return ErrConflict
+// addChild 添加子节点，静态子节点在前并按首字节排序，随后依次是 param 和 *
+// 子节点的顺序与添加顺序无关，相同的路由集合总是得到相同的树
+func (n *node[T]) addChild(child *node[T]) {
+	i := len(n.children)
+	for i > 0 && (n.children[i-1].kind > child.kind || child.kind == staticKind && n.indices[i-1] > child.path[0]) {
+		i--
+	}
+	n.indices = n.indices[:i] + child.path[:1] + n.indices[i:]
+	n.children = slices.Insert(n.children, i, child)
+	if child.kind != staticKind {
+		n.wildChild = true
+	}
+}
+
+func (n *node[T]) staticChild(c byte) int {
+	for i := 0; i < len(n.indices); i++ {
+		if n.children[i].kind != staticKind {
+			break
+		}
+		if n.indices[i] == c {
+			return i
 		}
 	}
+	return -1
+}
 
-	// 如果存在多个子节点，子节点必须同类，避免回退逻辑
-	// 由于只有 static 可以分裂，param 和 * 的公共前缀会阻止节点有多个 param 或 * 子节点
-	if len(n.children) > 0 && n.children[0].kind != child.kind {
-		return ErrConflict
+// split 在 l 处分裂静态节点，后半部分成为唯一的子节点
+func (n *node[T]) split(l int) {
+	child := *n
+	child.path = n.path[l:]
+	*n = node[T]{
+		kind:     staticKind,
+		path:     n.path[:l],
+		indices:  child.path[:1],
+		children: []*node[T]{&child},
 	}
+}
 
-	if child.kind != staticKind {
-		n.wildChild = true
+// insert 在子节点中插入 seg，返回 seg 对应的节点
+// n 必须是复制的节点，经过的子节点同样会被复制
+// 冲突时返回 *ConflictError，由调用方补充 Pattern 和 Offset
+func (n *node[T]) insert(seg *node[T]) (*node[T], error) {
+	if seg.kind != staticKind {
+		// 同一位置只能有一个 param 和一个 *，且必须完全相同
+		for i, child := range n.children {
+			if child.kind == seg.kind {
+				if child.path != seg.path {
+					return nil, &ConflictError{Existing: child.firstPattern(), Reason: conflictReason(child, seg)}
+				}
+				child = child.clone()
+				n.children[i] = child
+				return child, nil
+			}
+		}
+		n.addChild(seg)
+		return seg, nil
+	}
+
+	path := seg.path
+	for {
+		i := n.staticChild(path[0])
+		if i < 0 {
+			if n.kind == paramKind && path[0] != '/' {
+				// param 之后不同的静态文本可能同时出现在值中，例如 /:from-:to 和 /:from.:to 都能匹配 /1-2.3
+				for _, child := range n.children {
+					if child.kind == staticKind && child.path[0] != '/' {
+						return nil, &ConflictError{Existing: child.firstPattern(), Reason: "ambiguous literal after param"}
+					}
+				}
+			}
+			child := &node[T]{kind: staticKind, path: path}
+			n.addChild(child)
+			return child, nil
+		}
+		child := n.children[i].clone()
+		n.children[i] = child
+		l := commonPrefixLength(child.path, path)
+		if l < len(child.path) {
+			child.split(l)
+		}
+		n = child
+		path = path[l:]
+		if path == "" {
+			return n, nil
+		}
+	}
+}
+
+// find 查找 seg 对应的子节点，经过的节点依次追加到 stack，不存在时返回 nil
+func (n *node[T]) find(seg *node[T], stack []*node[T]) []*node[T] {
+	if seg.kind != staticKind {
+		for _, child := range n.children {
+			if child.kind == seg.kind && child.path == seg.path {
+				return append(stack, child)
+			}
+		}
+		return nil
+	}
+
+	path := seg.path
+	for path != "" {
+		i := n.staticChild(path[0])
+		if i < 0 || !strings.HasPrefix(path, n.children[i].path) {
+			return nil
+		}
+		n = n.children[i]
+		path = path[len(n.path):]
+		stack = append(stack, n)
+	}
+	return stack
+}
+
+// firstPattern 返回 n 之下第一个路由的 pattern，n 之下总是存在路由
+func (n *node[T]) firstPattern() string {
+	for !n.end {
+		n = n.children[0]
+	}
+	return n.pattern
+}
+
+func conflictReason[T any](child *node[T], seg *node[T]) string {
+	switch {
+	case child.kind == trailingKind:
+		return "catch-all name differs"
+	case child.key != seg.key:
+		return "param name differs"
+	}
+	return "param constraint differs"
+}
+
+func (n *node[T]) removeChild(child *node[T]) {
+	i := slices.Index(n.children, child)
+	n.indices = n.indices[:i] + n.indices[i+1:]
+	n.children = slices.Delete(n.children, i, i+1)
+	if len(n.children) == 0 {
+		n.children = nil
+	}
+	n.wildChild = len(n.children) > 0 && n.children[len(n.children)-1].kind != staticKind
+}
+
+// merge 将非终止的静态节点与唯一的静态子节点合并，与 split 相反
+func (n *node[T]) merge() {
+	if n.kind != staticKind || n.end || len(n.children) != 1 || n.children[0].kind != staticKind {
+		return
+	}
+	path := n.path + n.children[0].path
+	*n = *n.children[0]
+	n.path = path
+}
+
+// walk 遍历 n 及其子节点中的路由，prefix 是 n 之前的 pattern
+func (n *node[T]) walk(prefix string, fn func(pattern string, value T) bool) bool {
+	prefix += n.path
+	if n.end && !fn(prefix, n.value) {
+		return false
 	}
-	n.indices += child.path[:1]
-	n.children = append(n.children, child)
+	for _, child := range n.children {
+		if !child.walk(prefix, fn) {
+			return false
+		}
+	}
+	return true
+}
 
+// lookup 在子节点中匹配 path，n 自身已经完成匹配，返回终止节点
+// fn 不为 nil 时，终止节点还需要满足 fn，否则继续尝试其他节点
+func (n *node[T]) lookup(path string, res *MatchResult[T], fn func(*node[T]) bool) *node[T] {
+	if path == "" {
+		if n.end && (fn == nil || fn(n)) {
+			return n
+		}
+	} else if i := n.staticChild(path[0]); i >= 0 {
+		child := n.children[i]
+		if strings.HasPrefix(path, child.path) {
+			found := child.lookup(path[len(child.path):], res, fn)
+			if found != nil {
+				return found
+			}
+		}
+	}
+
+	if !n.wildChild {
+		return nil
+	}
+
+	i := len(n.children)
+	for i > 0 && n.children[i-1].kind != staticKind {
+		i--
+	}
+	for _, child := range n.children[i:] {
+		switch child.kind {
+		case paramKind:
+			// param 要求值非空
+			if path == "" {
+				continue
+			}
+			found := child.matchParam(path, res, fn)
+			if found != nil {
+				return found
+			}
+		case trailingKind:
+			if child.end {
+				mark := len(res.Params)
+				res.Params = append(res.Params, Param{Key: child.key, Value: path})
+				if fn == nil || fn(child) {
+					return child
+				}
+				res.Params = res.Params[:mark]
+			}
+		}
+	}
+	return nil
+}
+
+// matchParam 匹配 param 节点，param 捕获到其后的静态文本或下一个 / 之前
+// 子节点按照首字节的顺序尝试，静态文本在片段中多次出现时，从最近的位置开始依次尝试
+func (n *node[T]) matchParam(path string, res *MatchResult[T], fn func(*node[T]) bool) *node[T] {
+	end := strings.IndexByte(path, '/')
+	if end < 0 {
+		end = len(path)
+	}
+	mark := len(res.Params)
+	for _, child := range n.children {
+		c := child.path[0]
+		if c == '/' {
+			continue
+		}
+		for i := 1; i < end; i++ {
+			j := strings.IndexByte(path[i:end], c)
+			if j < 0 {
+				break
+			}
+			i += j
+			if strings.HasPrefix(path[i:], child.path) && n.accept(path[:i], res) {
+				found := child.lookup(path[i+len(child.path):], res, fn)
+				if found != nil {
+					return found
+				}
+				res.Params = res.Params[:mark]
+			}
+		}
+	}
+	if end > 0 && n.accept(path[:end], res) {
+		found := n.lookup(path[end:], res, fn)
+		if found != nil {
+			return found
+		}
+		res.Params = res.Params[:mark]
+	}
 	return nil
 }
 
-func (n *node[T]) init(path string, value T) error {
-	seg, path := splitPathSegment(path)
-	if seg == "*" {
+// accept 检查 param 的约束，满足时记录到 res
+func (n *node[T]) accept(value string, res *MatchResult[T]) bool {
+	if n.re != nil && !n.re.MatchString(value) {
+		return false
+	}
+	var typed any
+	if n.typ != nil {
+		v, ok := n.typ.conv(value)
+		if !ok {
+			return false
+		}
+		typed = v
+	}
+	res.Params = append(res.Params, Param{Key: n.key, Value: value, Typed: typed})
+	return true
+}
+
+func (n *node[T]) init(seg string) error {
+	if strings.HasPrefix(seg, "*") {
 		n.kind = trailingKind
-	} else if strings.HasPrefix(seg, ":") {
-		if len(seg) == 1 {
-			return ErrInvalidPath
+		// 未命名的 * 使用 "*" 作为 key
+		n.key = "*"
+		if len(seg) > 1 {
+			// 与 param 相同，名称由字母、数字和 _ 组成
+			for i := 1; i < len(seg); i++ {
+				if !isNameChar(seg[i]) {
+					return &PatternError{Offset: i, Reason: "invalid catch-all name"}
+				}
+			}
+			n.key = seg[1:]
 		}
+	} else if strings.HasPrefix(seg, ":") {
 		n.kind = paramKind
+		err := n.initParam(seg)
+		if err != nil {
+			return err
+		}
 	} else {
 		n.kind = staticKind
 	}
 	n.path = seg
+	return nil
+}
 
-	if path != "" {
-		c := &node[T]{}
-		err := c.init(path, value)
+// initParam 解析 :name、:name<type>、:name{regexp} 或 :name<type>{regexp}
+// 返回的 *PatternError 中 Offset 相对于 seg，由 parsePath 补充
+func (n *node[T]) initParam(seg string) error {
+	name := seg[1:]
+	brace := strings.IndexByte(name, '{')
+	if brace >= 0 {
+		if name[len(name)-1] != '}' {
+			return &PatternError{Offset: 1 + brace, Reason: "unclosed constraint"}
+		}
+		expr := name[brace+1 : len(name)-1]
+		if expr == "" {
+			// param 的值不能为空，空的约束永远无法匹配
+			return &PatternError{Offset: 1 + brace, Reason: "empty constraint"}
+		}
+		name = name[:brace]
+		re, err := regexp.Compile("^(?:" + expr + ")$")
 		if err != nil {
-			return err
+			return &PatternError{Offset: 1 + brace, Reason: "bad constraint", Err: err}
 		}
-		return n.addChild(c)
+		n.re = re
 	}
-
-	n.set(value)
+	lt := strings.IndexByte(name, '<')
+	if lt >= 0 {
+		if name[len(name)-1] != '>' {
+			return &PatternError{Offset: 1 + lt, Reason: "unclosed type"}
+		}
+		typ := lookupParamType(name[lt+1 : len(name)-1])
+		if typ == nil {
+			return &PatternError{Offset: 1 + lt, Reason: fmt.Sprintf("unknown type %q", name[lt+1:len(name)-1])}
+		}
+		n.typ = typ
+		name = name[:lt]
+	}
+	if name == "" {
+		return &PatternError{Offset: 1, Reason: "missing param name"}
+	}
+	n.key = name
 	return nil
 }
 
-func (n *node[T]) addSubPath(path string, value T) error {
-	if path == "" {
-		n.set(value)
-		return nil
-	}
-
-	child := &node[T]{}
-	err := child.init(path, value)
-	if err != nil {
-		return err
+// parsePath 将 path 拆分为未连接的节点，检查 param 和 * 的位置
+func parsePath[T any](path string) ([]*node[T], error) {
+	var segs []*node[T]
+	pattern := path
+	offset := 0
+	for path != "" {
+		var seg string
+		seg, path = splitPathSegment(path)
+		n := &node[T]{}
+		err := n.init(seg)
+		if err != nil {
+			if pe, ok := err.(*PatternError); ok {
+				pe.Pattern = pattern
+				pe.Offset += offset
+			}
+			return nil, err
+		}
+		if len(segs) > 0 {
+			// * 必须在末尾，param 之后必须是静态文本，相邻的 param 无法确定边界
+			switch prev := segs[len(segs)-1]; {
+			case prev.kind == trailingKind:
+				return nil, &PatternError{Pattern: pattern, Offset: offset, Reason: "catch-all must be at the end"}
+			case prev.kind == paramKind && n.kind != staticKind:
+				return nil, &PatternError{Pattern: pattern, Offset: offset, Reason: "param must be followed by static text"}
+			}
+		}
+		segs = append(segs, n)
+		offset += len(seg)
 	}
-
-	return n.addChild(child)
+	return segs, nil
 }
 
 func commonPrefixLength(a string, b string) int {
@@ -277,9 +690,22 @@ func commonPrefixLength(a string, b string) int {
 func splitPathSegment(path string) (string, string) {
 	if path != "" {
 		switch path[0] {
-		case '*':
-			return path[:1], path[1:]
 		case ':':
+			// param 名称由字母、数字和 _ 组成，之后可以跟随类型和约束
+			i := 1
+			for i < len(path) && isNameChar(path[i]) {
+				i++
+			}
+			if i < len(path) && (path[i] == '<' || path[i] == '{') {
+				// 类型和约束到 > 或匹配的 } 为止
+				end := constraintEnd(path[i:])
+				if end < 0 {
+					return path, ""
+				}
+				i += end
+			}
+			return path[:i], path[i:]
+		case '*':
 			for i := 1; i < len(path); i++ {
 				c := path[i]
 				if c == '/' || c == ':' || c == '*' {
@@ -297,3 +723,37 @@ func splitPathSegment(path string) (string, string) {
 	}
 	return path, ""
 }
+
+func isNameChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// constraintEnd 返回 <type>{regexp} 结束的位置，未闭合时返回 -1
+func constraintEnd(s string) int {
+	start := 0
+	if s[0] == '<' {
+		gt := strings.IndexByte(s, '>')
+		if gt < 0 {
+			return -1
+		}
+		start = gt + 1
+		if start == len(s) || s[start] != '{' {
+			return start
+		}
+	}
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
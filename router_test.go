@@ -1,10 +1,13 @@
 package pathrouter
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/vizee/pathrouter/internal/testroutes"
 )
 
 var kindNames = map[uint8]string{
@@ -93,6 +96,23 @@ func Test_splitPathSegment(t *testing.T) {
 		{args: args{":a:b"}, want: ":a", want1: ":b"},
 		{args: args{"**"}, want: "*", want1: "*"},
 		{args: args{":*"}, want: ":", want1: "*"},
+		{args: args{"*path"}, want: "*path", want1: ""},
+		{args: args{"*path/a"}, want: "*path", want1: "/a"},
+		{args: args{"*a:b"}, want: "*a", want1: ":b"},
+		{args: args{"*file.txt"}, want: "*file.txt", want1: ""},
+		{args: args{"*rest{x}/a"}, want: "*rest{x}", want1: "/a"},
+		{args: args{":id{[0-9]+}"}, want: ":id{[0-9]+}", want1: ""},
+		{args: args{":id{[0-9]{3}}/a"}, want: ":id{[0-9]{3}}", want1: "/a"},
+		{args: args{":id{a/b}c"}, want: ":id{a/b}", want1: "c"},
+		{args: args{`:id{\}}/a`}, want: `:id{\}}`, want1: "/a"},
+		{args: args{":id{[0-9]+/a"}, want: ":id{[0-9]+/a", want1: ""},
+		{args: args{"a{b}"}, want: "a{b}", want1: ""},
+		{args: args{":id<int>/a"}, want: ":id<int>", want1: "/a"},
+		{args: args{":id<int>{[0-9]+}/a"}, want: ":id<int>{[0-9]+}", want1: "/a"},
+		{args: args{":id<int/a"}, want: ":id<int/a", want1: ""},
+		{args: args{":name.:ext"}, want: ":name", want1: ".:ext"},
+		{args: args{":from-:to"}, want: ":from", want1: "-:to"},
+		{args: args{":id<int>.json"}, want: ":id<int>", want1: ".json"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -124,15 +144,40 @@ func TestRouter_Add(t *testing.T) {
 		{name: "param2", routes: []string{"/a/:a", "/b/:b"}},
 		{name: "trailing", routes: []string{"*"}},
 		{name: "trailing2", routes: []string{"/a/*", "/b/*"}},
+		{name: "trailing-named", routes: []string{"/static/*filepath"}},
+		{name: "trailing-named2", routes: []string{"/a/*x", "/b/*y"}},
 		{name: "bad1-0", routes: []string{"*:a"}, wantErr: ErrInvalidPath},
 		{name: "bad1-1", routes: []string{":"}, wantErr: ErrInvalidPath},
 		{name: "bad1-2", routes: []string{":a:b"}, wantErr: ErrInvalidPath},
 		{name: "bad1-3", routes: []string{":a*"}, wantErr: ErrInvalidPath},
 		{name: "bad2", routes: []string{"/a", "/a/*:a"}, wantErr: ErrInvalidPath},
-		{name: "conflict", routes: []string{"/a", "/:b"}, wantErr: ErrConflict},
+		{name: "multi-param", routes: []string{"/files/:name.:ext", "/range/:from-:to", "/files/:name"}},
+		{name: "multi-param-ambiguous", routes: []string{"/:from-:to", "/:from.:to"}, wantErr: ErrConflict},
+		{name: "multi-param-ambiguous2", routes: []string{"/files/:name.json", "/files/:name-:v"}, wantErr: ErrConflict},
+		{name: "multi-param-slash", routes: []string{"/:a-:b/x", "/:a/x", "/:a"}},
+		{name: "multi-param-bad", routes: []string{"/files/:name:ext"}, wantErr: ErrInvalidPath},
+		{name: "multi-param-trailing", routes: []string{"/files/:name.*"}},
+		{name: "multi-param-bad2", routes: []string{"/files/:name*"}, wantErr: ErrInvalidPath},
+		{name: "trailing-bad-name", routes: []string{"/static/*file.txt"}, wantErr: ErrInvalidPath},
+		{name: "trailing-bad-name2", routes: []string{"/a/*rest{x}"}, wantErr: ErrInvalidPath},
+		{name: "trailing-bad-name3", routes: []string{"/a/*-"}, wantErr: ErrInvalidPath},
+		{name: "trailing-name", routes: []string{"/a/*rest_1"}},
+		{name: "regexp", routes: []string{"/users/:id{[0-9]+}", "/users/:id{[0-9]+}/posts"}},
+		{name: "bad-regexp", routes: []string{"/users/:id{[0-9+}"}, wantErr: ErrInvalidPath},
+		{name: "bad-regexp2", routes: []string{"/users/:id{[0-9]+"}, wantErr: ErrInvalidPath},
+		{name: "bad-regexp3", routes: []string{"/users/:{[0-9]+}"}, wantErr: ErrInvalidPath},
+		{name: "empty-regexp", routes: []string{"/a/:b{}"}, wantErr: ErrInvalidPath},
+		{name: "static-param", routes: []string{"/a", "/:b"}},
+		{name: "static-param2", routes: []string{"/users/new", "/users/:id", "/users/*path"}},
+		{name: "static-trailing", routes: []string{"/a/b/:c", "/a/*"}},
 		{name: "conflict2", routes: []string{"/:a", "/:ab"}, wantErr: ErrConflict},
 		{name: "conflict3", routes: []string{"/:ab", "/:ac"}, wantErr: ErrConflict},
-		{name: "conflict4", routes: []string{"/a/b/:c", "/a/*"}, wantErr: ErrConflict},
+		{name: "conflict5", routes: []string{"/a/*x", "/a/*y"}, wantErr: ErrConflict},
+		{name: "conflict6", routes: []string{"/a/*", "/a/*x"}, wantErr: ErrConflict},
+		{name: "conflict7", routes: []string{"/a/*x", "/a/*"}, wantErr: ErrConflict},
+		{name: "conflict-regexp", routes: []string{"/:id{[0-9]+}", "/:id{[a-z]+}"}, wantErr: ErrConflict},
+		{name: "conflict-regexp2", routes: []string{"/:id", "/:id{[0-9]+}"}, wantErr: ErrConflict},
+		{name: "conflict-regexp3", routes: []string{"/:id{[0-9]+}", "/:id"}, wantErr: ErrConflict},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -141,7 +186,7 @@ func TestRouter_Add(t *testing.T) {
 			for i, route := range tt.routes {
 				err := r.Add(route, 100+i)
 				if err != nil {
-					if err != tt.wantErr {
+					if !errors.Is(err, tt.wantErr) {
 						t.Fatalf("Router.Add() error = %v, wantErr %v", err, tt.wantErr)
 					}
 					errorOccurred = true
@@ -157,6 +202,74 @@ func TestRouter_Add(t *testing.T) {
 	}
 }
 
+func TestRouter_Duplicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    DuplicatePolicy
+		wantErr   error
+		wantValue int
+	}{
+		{name: "replace", policy: DuplicateReplace, wantValue: 102},
+		{name: "error", policy: DuplicateError, wantErr: ErrDuplicate, wantValue: 100},
+		{name: "ignore", policy: DuplicateIgnore, wantValue: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Router[int]{Duplicate: tt.policy}
+			if err := r.Add("/a/:b", 100); err != nil {
+				t.Fatalf("Router.Add() error = %v", err)
+			}
+			if err := r.Add("/a/:b/c", 101); err != nil {
+				t.Fatalf("Router.Add() error = %v", err)
+			}
+			if err := r.Add("/a/:b", 102); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Router.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var res MatchResult[int]
+			if !r.Match("/a/x", &res) || res.Value != tt.wantValue {
+				t.Fatalf("Router.Match() got %v, want %v", res.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestRouter_InsertUpdateUpsert(t *testing.T) {
+	r := &Router[int]{}
+	if err := r.Update("/a", 100); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Router.Update() error = %v, wantErr %v", err, ErrNotFound)
+	}
+	if err := r.Insert("/a", 101); err != nil {
+		t.Fatalf("Router.Insert() error = %v", err)
+	}
+	if err := r.Insert("/a", 102); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("Router.Insert() error = %v, wantErr %v", err, ErrDuplicate)
+	}
+	if err := r.Update("/a", 103); err != nil {
+		t.Fatalf("Router.Update() error = %v", err)
+	}
+	if err := r.Update("/ab", 104); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Router.Update() error = %v, wantErr %v", err, ErrNotFound)
+	}
+	if err := r.Insert("/ab", 105); err != nil {
+		t.Fatalf("Router.Insert() error = %v", err)
+	}
+	if err := r.Update("/a", 106); err != nil {
+		t.Fatalf("Router.Update() error = %v", err)
+	}
+	if err := r.Upsert("/ab", 107); err != nil {
+		t.Fatalf("Router.Upsert() error = %v", err)
+	}
+	if err := r.Upsert("/abc", 108); err != nil {
+		t.Fatalf("Router.Upsert() error = %v", err)
+	}
+	for path, want := range map[string]int{"/a": 106, "/ab": 107, "/abc": 108} {
+		var res MatchResult[int]
+		if !r.Match(path, &res) || res.Value != want {
+			t.Errorf("Router.Match(%q) got %v, want %v", path, res.Value, want)
+		}
+	}
+}
+
 func buildRouter(routes []string) *Router[int] {
 	r := &Router[int]{}
 	for value, path := range routes {
@@ -176,34 +289,81 @@ func TestRouter_Match(t *testing.T) {
 		path    string
 		want    bool
 		wantRes MatchResult[int]
+		// res 是复用的 MatchResult 在匹配前的值
+		res MatchResult[int]
 	}{
 		{name: "nil", path: "", want: false},
-		{name: "empty", routes: []string{""}, path: "", want: true, wantRes: MatchResult[int]{Value: 100}},
-		{name: "empty", routes: []string{"a", "b", ""}, path: "", want: true, wantRes: MatchResult[int]{Value: 102}},
+		{name: "nil-dirty", path: "/zzz", want: false, res: MatchResult[int]{Value: 100, Pattern: "/a/", TSR: true}},
+		{name: "dirty-fail", routes: []string{"/a/"}, path: "/zzz", want: false, res: MatchResult[int]{Value: 100, Pattern: "/a/", TSR: true}},
+		{name: "empty", routes: []string{""}, path: "", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: ""}},
+		{name: "empty", routes: []string{"a", "b", ""}, path: "", want: true, wantRes: MatchResult[int]{Value: 102, Pattern: ""}},
 		{name: "empty-fail", routes: []string{"a", "b"}, path: "", want: false},
-		{name: "static", routes: []string{"/a", "/a/b", "/a/c"}, path: "/a/b", want: true, wantRes: MatchResult[int]{Value: 101}},
+		{name: "static", routes: []string{"/a", "/a/b", "/a/c"}, path: "/a/b", want: true, wantRes: MatchResult[int]{Value: 101, Pattern: "/a/b"}},
 		{name: "static-fail", routes: []string{"/a", "/a/b", "/a/c"}, path: "/a/d", want: false},
-		{name: "param", routes: []string{"/a/:param1", "/a/:param1/:param2", "/b/:param3"}, path: "/a/d", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "d"}}, Value: 100}},
-		{name: "param-1", routes: []string{"/a/:param1", "/a/:param1/:param2", "/b/:param3"}, path: "/a/b/c", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "b"}, {Key: "param2", Value: "c"}}, Value: 101}},
-		{name: "param-2", routes: []string{"/a/:param1", "/a/:param1/:param2", "/b/:param3"}, path: "/b/123", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param3", Value: "123"}}, Value: 102}},
-		{name: "param-prefix", routes: []string{"/a/a-:param1", "/a/b-:param2"}, path: "/a/b-123", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param2", Value: "123"}}, Value: 101}},
+		{name: "param", routes: []string{"/a/:param1", "/a/:param1/:param2", "/b/:param3"}, path: "/a/d", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "d"}}, Value: 100, Pattern: "/a/:param1"}},
+		{name: "param-1", routes: []string{"/a/:param1", "/a/:param1/:param2", "/b/:param3"}, path: "/a/b/c", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "b"}, {Key: "param2", Value: "c"}}, Value: 101, Pattern: "/a/:param1/:param2"}},
+		{name: "param-2", routes: []string{"/a/:param1", "/a/:param1/:param2", "/b/:param3"}, path: "/b/123", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param3", Value: "123"}}, Value: 102, Pattern: "/b/:param3"}},
+		{name: "param-prefix", routes: []string{"/a/a-:param1", "/a/b-:param2"}, path: "/a/b-123", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param2", Value: "123"}}, Value: 101, Pattern: "/a/b-:param2"}},
 		{name: "param-fail", routes: []string{"/a/:param1"}, path: "/b/d", want: false},
 		{name: "param-fail-1", routes: []string{":param1"}, path: "", want: false},
-		{name: "param-fail-2", routes: []string{"/a/:param1"}, path: "/a/d/", want: false},
-		{name: "trailing", routes: []string{"/a/:param1/*", "/b/*"}, path: "/a/b/c", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "b"}, {Key: "*", Value: "c"}}, Value: 100}},
-		{name: "trailing-1", routes: []string{"/a/:param1/*", "/b/*"}, path: "/b/123", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "123"}}, Value: 101}},
-		{name: "trailing-2", routes: []string{"*"}, path: "", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: ""}}, Value: 100}},
-		{name: "trailing-3", routes: []string{"/a/*"}, path: "/a/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: ""}}, Value: 100}},
-		{name: "trailing-4", routes: []string{"/a/", "/a/*"}, path: "/a/", want: true, wantRes: MatchResult[int]{Value: 100}},
+		{name: "param-fail-2", routes: []string{"/a/:param1"}, path: "/a/d/", want: false, wantRes: MatchResult[int]{TSR: true}},
+		{name: "trailing", routes: []string{"/a/:param1/*", "/b/*"}, path: "/a/b/c", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "b"}, {Key: "*", Value: "c"}}, Value: 100, Pattern: "/a/:param1/*"}},
+		{name: "trailing-1", routes: []string{"/a/:param1/*", "/b/*"}, path: "/b/123", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: "123"}}, Value: 101, Pattern: "/b/*"}},
+		{name: "trailing-2", routes: []string{"*"}, path: "", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: ""}}, Value: 100, Pattern: "*"}},
+		{name: "trailing-3", routes: []string{"/a/*"}, path: "/a/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "*", Value: ""}}, Value: 100, Pattern: "/a/*"}},
+		{name: "trailing-4", routes: []string{"/a/", "/a/*"}, path: "/a/", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: "/a/"}},
+		{name: "priority", routes: []string{"/users/new", "/users/:id", "/users/*path"}, path: "/users/new", want: true, wantRes: MatchResult[int]{Value: 100, Pattern: "/users/new"}},
+		{name: "priority-1", routes: []string{"/users/new", "/users/:id", "/users/*path"}, path: "/users/42", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "42"}}, Value: 101, Pattern: "/users/:id"}},
+		{name: "priority-2", routes: []string{"/users/new", "/users/:id", "/users/*path"}, path: "/users/42/posts", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "path", Value: "42/posts"}}, Value: 102, Pattern: "/users/*path"}},
+		{name: "priority-3", routes: []string{"/users/new", "/users/:id", "/users/*path"}, path: "/users/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "path", Value: ""}}, Value: 102, Pattern: "/users/*path"}},
+		{name: "backtrack", routes: []string{"/users/new", "/users/:id/posts"}, path: "/users/new/posts", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "new"}}, Value: 101, Pattern: "/users/:id/posts"}},
+		{name: "backtrack-1", routes: []string{"/users/newest", "/users/:id/posts"}, path: "/users/new/posts", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "new"}}, Value: 101, Pattern: "/users/:id/posts"}},
+		{name: "backtrack-2", routes: []string{"/a/:b/c", "/a/:b/:d/e", "/a/*f"}, path: "/a/x/y/z", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "f", Value: "x/y/z"}}, Value: 102, Pattern: "/a/*f"}},
+		{name: "backtrack-3", routes: []string{"/a/:b/c", "/a/:b/:d/e", "/a/*f"}, path: "/a/x/y/e", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "b", Value: "x"}, {Key: "d", Value: "y"}}, Value: 101, Pattern: "/a/:b/:d/e"}},
+		{name: "backtrack-regexp", routes: []string{"/a/:id{[0-9]+}", "/a/:id{[0-9]+}/b", "/a/*rest"}, path: "/a/x/b", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "rest", Value: "x/b"}}, Value: 102, Pattern: "/a/*rest"}},
+		{name: "backtrack-fail", routes: []string{"/users/new", "/users/:id/posts"}, path: "/users/new/comments", want: false},
+		{name: "multi-param", routes: []string{"/files/:name.:ext", "/range/:from-:to"}, path: "/files/a.txt", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "name", Value: "a"}, {Key: "ext", Value: "txt"}}, Value: 100, Pattern: "/files/:name.:ext"}},
+		{name: "multi-param-1", routes: []string{"/files/:name.:ext", "/range/:from-:to"}, path: "/range/1-10", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "from", Value: "1"}, {Key: "to", Value: "10"}}, Value: 101, Pattern: "/range/:from-:to"}},
+		{name: "multi-param-2", routes: []string{"/files/:name.:ext", "/range/:from-:to"}, path: "/files/a.tar.gz", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "name", Value: "a"}, {Key: "ext", Value: "tar.gz"}}, Value: 100, Pattern: "/files/:name.:ext"}},
+		{name: "multi-param-3", routes: []string{"/files/:name.:ext", "/files/:name"}, path: "/files/a", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "name", Value: "a"}}, Value: 101, Pattern: "/files/:name"}},
+		{name: "multi-param-4", routes: []string{"/files/:name.json", "/files/:name"}, path: "/files/a.txt", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "name", Value: "a.txt"}}, Value: 101, Pattern: "/files/:name"}},
+		{name: "multi-param-5", routes: []string{"/js/:name.js"}, path: "/js/jquery.min.js", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "name", Value: "jquery.min"}}, Value: 100, Pattern: "/js/:name.js"}},
+		{name: "multi-param-6", routes: []string{"/:name.:ext/raw"}, path: "/a.b/raw", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "name", Value: "a"}, {Key: "ext", Value: "b"}}, Value: 100, Pattern: "/:name.:ext/raw"}},
+		{name: "multi-param-7", routes: []string{"/v/:id<int>.json"}, path: "/v/12.json", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "12", Typed: int64(12)}}, Value: 100, Pattern: "/v/:id<int>.json"}},
+		{name: "multi-param-slash", routes: []string{"/:a/x", "/:a-:b/x"}, path: "/1-2/x", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, Value: 101, Pattern: "/:a-:b/x"}},
+		{name: "multi-param-fail", routes: []string{"/files/:name.:ext"}, path: "/files/.txt", want: false},
+		{name: "multi-param-fail-1", routes: []string{"/files/:name.:ext"}, path: "/files/a.", want: false},
+		{name: "multi-param-fail-2", routes: []string{"/files/:name.:ext"}, path: "/files/a/b.c", want: false},
+		{name: "param-empty-fail", routes: []string{"/:a/b"}, path: "//b", want: false},
+		{name: "regexp", routes: []string{"/users/:id{[0-9]+}", "/users/:id{[0-9]+}/posts"}, path: "/users/42", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "42"}}, Value: 100, Pattern: "/users/:id{[0-9]+}"}},
+		{name: "regexp-1", routes: []string{"/users/:id{[0-9]+}", "/users/:id{[0-9]+}/posts"}, path: "/users/42/posts", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "42"}}, Value: 101, Pattern: "/users/:id{[0-9]+}/posts"}},
+		{name: "regexp-fail", routes: []string{"/users/:id{[0-9]+}"}, path: "/users/abc", want: false},
+		{name: "regexp-fail-1", routes: []string{"/users/:id{[0-9]+}"}, path: "/users/42abc", want: false},
+		{name: "tsr", routes: []string{"/a/", "/b", "/c/:id"}, path: "/a", want: false, wantRes: MatchResult[int]{TSR: true}},
+		{name: "tsr-1", routes: []string{"/a/", "/b", "/c/:id"}, path: "/b/", want: false, wantRes: MatchResult[int]{TSR: true}},
+		{name: "tsr-2", routes: []string{"/a/", "/b", "/c/:id"}, path: "/c/1/", want: false, wantRes: MatchResult[int]{TSR: true}},
+		{name: "tsr-3", routes: []string{"/a/*rest"}, path: "/a", want: false, wantRes: MatchResult[int]{TSR: true}},
+		{name: "tsr-4", routes: []string{"/"}, path: "", want: false, wantRes: MatchResult[int]{TSR: true}},
+		{name: "tsr-fail", routes: []string{"/a/", "/b", "/c/:id"}, path: "/c/", want: false},
+		{name: "tsr-fail-1", routes: []string{"/a/", "/b", "/c/:id"}, path: "/b//", want: false},
+		{name: "trailing-named", routes: []string{"/static/*filepath"}, path: "/static/css/a.css", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "filepath", Value: "css/a.css"}}, Value: 100, Pattern: "/static/*filepath"}},
+		{name: "trailing-named-1", routes: []string{"/a/:param1/*rest", "/b/*path"}, path: "/a/b/c/d", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "param1", Value: "b"}, {Key: "rest", Value: "c/d"}}, Value: 100, Pattern: "/a/:param1/*rest"}},
+		{name: "trailing-named-2", routes: []string{"/a/:param1/*rest", "/b/*path"}, path: "/b/", want: true, wantRes: MatchResult[int]{Params: Params{{Key: "path", Value: ""}}, Value: 101, Pattern: "/b/*path"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := buildRouter(tt.routes)
-			var res MatchResult[int]
+			res := tt.res
 			if got := r.Match(tt.path, &res); got != tt.want {
 				t.Errorf("Router.Match() = %v, want %v", got, tt.want)
 				return
 			}
+			if !tt.want && res.TSR != tt.wantRes.TSR {
+				t.Errorf("MatchResult.TSR got %v, want %v", res.TSR, tt.wantRes.TSR)
+			}
+			if !tt.want && res.Pattern != "" {
+				t.Errorf("MatchResult.Pattern got %q, want empty", res.Pattern)
+			}
 			if tt.want && !reflect.DeepEqual(res, tt.wantRes) {
 				t.Errorf("MatchResult got %+v, want %+v", res, tt.wantRes)
 			}
@@ -211,233 +371,212 @@ func TestRouter_Match(t *testing.T) {
 	}
 }
 
-var githubRoutes = []*struct {
-	Method string
-	Path   string
-}{
-	// OAuth Authorizations
-	{"GET", "/authorizations"},
-	{"GET", "/authorizations/:id"},
-	{"POST", "/authorizations"},
-	{"DELETE", "/authorizations/:id"},
-	{"GET", "/applications/:client_id/tokens/:access_token"},
-	{"DELETE", "/applications/:client_id/tokens"},
-	{"DELETE", "/applications/:client_id/tokens/:access_token"},
+var githubRoutes = testroutes.GitHub
 
-	// Activity
-	{"GET", "/events"},
-	{"GET", "/repos/:owner/:repo/events"},
-	{"GET", "/networks/:owner/:repo/events"},
-	{"GET", "/orgs/:org/events"},
-	{"GET", "/users/:user/received_events"},
-	{"GET", "/users/:user/received_events/public"},
-	{"GET", "/users/:user/events"},
-	{"GET", "/users/:user/events/public"},
-	{"GET", "/users/:user/events/orgs/:org"},
-	{"GET", "/feeds"},
-	{"GET", "/notifications"},
-	{"GET", "/repos/:owner/:repo/notifications"},
-	{"PUT", "/notifications"},
-	{"PUT", "/repos/:owner/:repo/notifications"},
-	{"GET", "/notifications/threads/:id"},
-	{"GET", "/notifications/threads/:id/subscription"},
-	{"PUT", "/notifications/threads/:id/subscription"},
-	{"DELETE", "/notifications/threads/:id/subscription"},
-	{"GET", "/repos/:owner/:repo/stargazers"},
-	{"GET", "/users/:user/starred"},
-	{"GET", "/user/starred"},
-	{"GET", "/user/starred/:owner/:repo"},
-	{"PUT", "/user/starred/:owner/:repo"},
-	{"DELETE", "/user/starred/:owner/:repo"},
-	{"GET", "/repos/:owner/:repo/subscribers"},
-	{"GET", "/users/:user/subscriptions"},
-	{"GET", "/user/subscriptions"},
-	{"GET", "/repos/:owner/:repo/subscription"},
-	{"PUT", "/repos/:owner/:repo/subscription"},
-	{"DELETE", "/repos/:owner/:repo/subscription"},
-	{"GET", "/user/subscriptions/:owner/:repo"},
-	{"PUT", "/user/subscriptions/:owner/:repo"},
-	{"DELETE", "/user/subscriptions/:owner/:repo"},
+func TestRouter_Remove(t *testing.T) {
+	routes := []string{
+		"",
+		"/a",
+		"/ab",
+		"/abc",
+		"/abd",
+		"/a/:b",
+		"/a/:b/c",
+		"/a/*rest",
+		"/files/:name.:ext",
+		"/files/:name.json",
+		"/files/:name",
+		"/range/:from-:to",
+	}
+	seen := map[string]bool{}
+	for _, route := range githubRoutes {
+		if !seen[route.Path] {
+			seen[route.Path] = true
+			routes = append(routes, route.Path)
+		}
+	}
+	build := func(routes []string, skip int) *Router[int] {
+		r := &Router[int]{}
+		for i, path := range routes {
+			if i != skip {
+				r.Add(path, 100+i)
+			}
+		}
+		return r
+	}
 
-	// Gists
-	{"GET", "/users/:user/gists"},
-	{"GET", "/gists"},
-	{"GET", "/gists/:id"},
-	{"POST", "/gists"},
-	{"DELETE", "/gists/:id/star"},
-	{"GET", "/gists/:id/star"},
-	{"POST", "/gists/:id/forks"},
-	{"DELETE", "/gists/:id"},
+	for i, path := range routes {
+		r := build(routes, -1)
+		if !r.Remove(path) {
+			t.Fatalf("Router.Remove(%q) = false", path)
+		}
+		if !reflect.DeepEqual(r, build(routes, i)) {
+			t.Fatalf("Router.Remove(%q) tree differs from tree built without it", path)
+		}
+		var res MatchResult[int]
+		if p, _ := githubPath(path); r.Match(p, &res) && res.Value == 100+i {
+			t.Fatalf("Router.Match(%q) matched removed route", p)
+		}
+		if r.Remove(path) {
+			t.Fatalf("Router.Remove(%q) = true after removal", path)
+		}
+	}
 
-	// Git Data
-	{"GET", "/repos/:owner/:repo/git/blobs/:sha"},
-	{"POST", "/repos/:owner/:repo/git/blobs"},
-	{"GET", "/repos/:owner/:repo/git/commits/:sha"},
-	{"POST", "/repos/:owner/:repo/git/commits"},
-	{"GET", "/repos/:owner/:repo/git/refs"},
-	{"POST", "/repos/:owner/:repo/git/refs"},
-	{"GET", "/repos/:owner/:repo/git/tags/:sha"},
-	{"POST", "/repos/:owner/:repo/git/tags"},
-	{"GET", "/repos/:owner/:repo/git/trees/:sha"},
-	{"POST", "/repos/:owner/:repo/git/trees"},
+	r := build(routes, -1)
+	for _, path := range routes {
+		r.Remove(path)
+	}
+	if r.root != nil {
+		t.Fatalf("Router.Remove() left nodes in empty router")
+	}
+	res := MatchResult[int]{Pattern: "/a", TSR: true}
+	if r.Match("/a", &res) || res.Pattern != "" || res.TSR {
+		t.Fatalf("Router.Match() on emptied router got %+v", res)
+	}
+
+	r = build([]string{"/abc", "/a/:b"}, -1)
+	for _, path := range []string{"/ab", "/a", "/a/:c", "/a/*", "/abcd", ":"} {
+		if r.Remove(path) {
+			t.Errorf("Router.Remove(%q) = true for missing route", path)
+		}
+	}
+}
+
+func TestRouter_Walk(t *testing.T) {
+	routes := []string{"/b", "/a/*rest", "/a/:id{[0-9]+}", "/a/new", "", "/files/:name.:ext", "/a/:id{[0-9]+}/x", "/ab"}
+	want := []string{"", "/a/new", "/a/:id{[0-9]+}", "/a/:id{[0-9]+}/x", "/a/*rest", "/ab", "/b", "/files/:name.:ext"}
+	r := buildRouter(routes)
+	var got []string
+	values := map[string]int{}
+	for pattern, value := range r.All() {
+		got = append(got, pattern)
+		values[pattern] = value
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Router.All() got %q, want %q", got, want)
+	}
+	for i, route := range routes {
+		if values[route] != 100+i {
+			t.Errorf("Router.All() value of %q got %v, want %v", route, values[route], 100+i)
+		}
+	}
 
-	// Issues
-	{"GET", "/issues"},
-	{"GET", "/user/issues"},
-	{"GET", "/orgs/:org/issues"},
-	{"GET", "/repos/:owner/:repo/issues"},
-	{"GET", "/repos/:owner/:repo/issues/:number"},
-	{"POST", "/repos/:owner/:repo/issues"},
-	{"GET", "/repos/:owner/:repo/assignees"},
-	{"GET", "/repos/:owner/:repo/assignees/:assignee"},
-	{"GET", "/repos/:owner/:repo/issues/:number/comments"},
-	{"POST", "/repos/:owner/:repo/issues/:number/comments"},
-	{"GET", "/repos/:owner/:repo/issues/:number/events"},
-	{"GET", "/repos/:owner/:repo/labels"},
-	{"GET", "/repos/:owner/:repo/labels/:name"},
-	{"POST", "/repos/:owner/:repo/labels"},
-	{"DELETE", "/repos/:owner/:repo/labels/:name"},
-	{"GET", "/repos/:owner/:repo/issues/:number/labels"},
-	{"POST", "/repos/:owner/:repo/issues/:number/labels"},
-	{"DELETE", "/repos/:owner/:repo/issues/:number/labels/:name"},
-	{"PUT", "/repos/:owner/:repo/issues/:number/labels"},
-	{"DELETE", "/repos/:owner/:repo/issues/:number/labels"},
-	{"GET", "/repos/:owner/:repo/milestones/:number/labels"},
-	{"GET", "/repos/:owner/:repo/milestones"},
-	{"GET", "/repos/:owner/:repo/milestones/:number"},
-	{"POST", "/repos/:owner/:repo/milestones"},
-	{"DELETE", "/repos/:owner/:repo/milestones/:number"},
+	got = got[:0]
+	r.Walk(func(pattern string, value int) bool {
+		got = append(got, pattern)
+		return len(got) < 3
+	})
+	if !reflect.DeepEqual(got, want[:3]) {
+		t.Fatalf("Router.Walk() got %q, want %q", got, want[:3])
+	}
 
-	// Miscellaneous
-	{"GET", "/emojis"},
-	{"GET", "/gitignore/templates"},
-	{"GET", "/gitignore/templates/:name"},
-	{"POST", "/markdown"},
-	{"POST", "/markdown/raw"},
-	{"GET", "/meta"},
-	{"GET", "/rate_limit"},
+	// 遍历的结果与添加顺序无关
+	r = &Router[int]{}
+	for i := len(githubRoutes) - 1; i >= 0; i-- {
+		r.Add(githubRoutes[i].Path, 0)
+	}
+	var reversed []string
+	for pattern := range r.All() {
+		reversed = append(reversed, pattern)
+	}
+	r = &Router[int]{}
+	for _, route := range githubRoutes {
+		r.Add(route.Path, 0)
+	}
+	got = got[:0]
+	for pattern := range r.All() {
+		got = append(got, pattern)
+	}
+	if !reflect.DeepEqual(got, reversed) {
+		t.Fatal("Router.All() order depends on insertion order")
+	}
 
-	// Organizations
-	{"GET", "/users/:user/orgs"},
-	{"GET", "/user/orgs"},
-	{"GET", "/orgs/:org"},
-	{"GET", "/orgs/:org/members"},
-	{"GET", "/orgs/:org/members/:user"},
-	{"DELETE", "/orgs/:org/members/:user"},
-	{"GET", "/orgs/:org/public_members"},
-	{"GET", "/orgs/:org/public_members/:user"},
-	{"PUT", "/orgs/:org/public_members/:user"},
-	{"DELETE", "/orgs/:org/public_members/:user"},
-	{"GET", "/orgs/:org/teams"},
-	{"GET", "/teams/:id"},
-	{"POST", "/orgs/:org/teams"},
-	{"DELETE", "/teams/:id"},
-	{"GET", "/teams/:id/members"},
-	{"GET", "/teams/:id/members/:user"},
-	{"PUT", "/teams/:id/members/:user"},
-	{"DELETE", "/teams/:id/members/:user"},
-	{"GET", "/teams/:id/repos"},
-	{"GET", "/teams/:id/repos/:owner/:repo"},
-	{"PUT", "/teams/:id/repos/:owner/:repo"},
-	{"DELETE", "/teams/:id/repos/:owner/:repo"},
-	{"GET", "/user/teams"},
+	var empty Router[int]
+	for pattern := range empty.All() {
+		t.Fatalf("empty Router.All() yields %q", pattern)
+	}
+}
 
-	// Pull Requests
-	{"GET", "/repos/:owner/:repo/pulls"},
-	{"GET", "/repos/:owner/:repo/pulls/:number"},
-	{"POST", "/repos/:owner/:repo/pulls"},
-	{"GET", "/repos/:owner/:repo/pulls/:number/commits"},
-	{"GET", "/repos/:owner/:repo/pulls/:number/files"},
-	{"GET", "/repos/:owner/:repo/pulls/:number/merge"},
-	{"PUT", "/repos/:owner/:repo/pulls/:number/merge"},
-	{"GET", "/repos/:owner/:repo/pulls/:number/comments"},
-	{"PUT", "/repos/:owner/:repo/pulls/:number/comments"},
+// githubPath 将 pattern 中的 param 替换为 param 名称
+func githubPath(pattern string) (string, Params) {
+	var ps Params
+	segs := strings.Split(pattern, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") {
+			segs[i] = seg[1:]
+			ps = append(ps, Param{Key: seg[1:], Value: seg[1:]})
+		}
+	}
+	return strings.Join(segs, "/"), ps
+}
 
-	// Repositories
-	{"GET", "/user/repos"},
-	{"GET", "/users/:user/repos"},
-	{"GET", "/orgs/:org/repos"},
-	{"GET", "/repositories"},
-	{"POST", "/user/repos"},
-	{"POST", "/orgs/:org/repos"},
-	{"GET", "/repos/:owner/:repo"},
-	{"GET", "/repos/:owner/:repo/contributors"},
-	{"GET", "/repos/:owner/:repo/languages"},
-	{"GET", "/repos/:owner/:repo/teams"},
-	{"GET", "/repos/:owner/:repo/tags"},
-	{"GET", "/repos/:owner/:repo/branches"},
-	{"GET", "/repos/:owner/:repo/branches/:branch"},
-	{"DELETE", "/repos/:owner/:repo"},
-	{"GET", "/repos/:owner/:repo/collaborators"},
-	{"GET", "/repos/:owner/:repo/collaborators/:user"},
-	{"PUT", "/repos/:owner/:repo/collaborators/:user"},
-	{"DELETE", "/repos/:owner/:repo/collaborators/:user"},
-	{"GET", "/repos/:owner/:repo/comments"},
-	{"GET", "/repos/:owner/:repo/commits/:sha/comments"},
-	{"POST", "/repos/:owner/:repo/commits/:sha/comments"},
-	{"GET", "/repos/:owner/:repo/comments/:id"},
-	{"DELETE", "/repos/:owner/:repo/comments/:id"},
-	{"GET", "/repos/:owner/:repo/commits"},
-	{"GET", "/repos/:owner/:repo/commits/:sha"},
-	{"GET", "/repos/:owner/:repo/readme"},
-	{"GET", "/repos/:owner/:repo/keys"},
-	{"GET", "/repos/:owner/:repo/keys/:id"},
-	{"POST", "/repos/:owner/:repo/keys"},
-	{"DELETE", "/repos/:owner/:repo/keys/:id"},
-	{"GET", "/repos/:owner/:repo/downloads"},
-	{"GET", "/repos/:owner/:repo/downloads/:id"},
-	{"DELETE", "/repos/:owner/:repo/downloads/:id"},
-	{"GET", "/repos/:owner/:repo/forks"},
-	{"POST", "/repos/:owner/:repo/forks"},
-	{"GET", "/repos/:owner/:repo/hooks"},
-	{"GET", "/repos/:owner/:repo/hooks/:id"},
-	{"POST", "/repos/:owner/:repo/hooks"},
-	{"POST", "/repos/:owner/:repo/hooks/:id/tests"},
-	{"DELETE", "/repos/:owner/:repo/hooks/:id"},
-	{"POST", "/repos/:owner/:repo/merges"},
-	{"GET", "/repos/:owner/:repo/releases"},
-	{"GET", "/repos/:owner/:repo/releases/:id"},
-	{"POST", "/repos/:owner/:repo/releases"},
-	{"DELETE", "/repos/:owner/:repo/releases/:id"},
-	{"GET", "/repos/:owner/:repo/releases/:id/assets"},
-	{"GET", "/repos/:owner/:repo/stats/contributors"},
-	{"GET", "/repos/:owner/:repo/stats/commit_activity"},
-	{"GET", "/repos/:owner/:repo/stats/code_frequency"},
-	{"GET", "/repos/:owner/:repo/stats/participation"},
-	{"GET", "/repos/:owner/:repo/stats/punch_card"},
-	{"GET", "/repos/:owner/:repo/statuses/:ref"},
-	{"POST", "/repos/:owner/:repo/statuses/:ref"},
+func TestRouter_MatchGithub(t *testing.T) {
+	// 在 GitHub 路由表中加入与 param 同级的静态路由
+	extra := []string{
+		"/users/new",
+		"/users/new/repos/starred",
+		"/gists/public",
+		"/repos/:owner/:repo/issues/new",
+		"/repos/vizee/:repo",
+		"/orgs/:org/*rest",
+	}
+	r := &Router[int]{}
+	values := map[string]int{}
+	for i, route := range githubRoutes {
+		values[route.Path] = 100 + i
+		err := r.Add(route.Path, 100+i)
+		if err != nil {
+			t.Fatalf("Router.Add(%q) error = %v", route.Path, err)
+		}
+	}
+	for i, path := range extra {
+		values[path] = 1000 + i
+		err := r.Add(path, 1000+i)
+		if err != nil {
+			t.Fatalf("Router.Add(%q) error = %v", path, err)
+		}
+	}
 
-	// Search
-	{"GET", "/search/repositories"},
-	{"GET", "/search/code"},
-	{"GET", "/search/issues"},
-	{"GET", "/search/users"},
-	{"GET", "/legacy/issues/search/:owner/:repository/:state/:keyword"},
-	{"GET", "/legacy/repos/search/:keyword"},
-	{"GET", "/legacy/user/search/:keyword"},
-	{"GET", "/legacy/user/email/:email"},
+	for _, route := range githubRoutes {
+		path, ps := githubPath(route.Path)
+		var res MatchResult[int]
+		if !r.Match(path, &res) {
+			t.Errorf("Router.Match(%q) = false", path)
+			continue
+		}
+		want := MatchResult[int]{Params: ps, Value: values[route.Path], Pattern: route.Path}
+		if !reflect.DeepEqual(res, want) {
+			t.Errorf("Router.Match(%q) got %+v, want %+v", path, res, want)
+		}
+	}
 
-	// Users
-	{"GET", "/users/:user"},
-	{"GET", "/user"},
-	{"GET", "/users"},
-	{"GET", "/user/emails"},
-	{"POST", "/user/emails"},
-	{"DELETE", "/user/emails"},
-	{"GET", "/users/:user/followers"},
-	{"GET", "/user/followers"},
-	{"GET", "/users/:user/following"},
-	{"GET", "/user/following"},
-	{"GET", "/user/following/:user"},
-	{"GET", "/users/:user/following/:target_user"},
-	{"PUT", "/user/following/:user"},
-	{"DELETE", "/user/following/:user"},
-	{"GET", "/users/:user/keys"},
-	{"GET", "/user/keys"},
-	{"GET", "/user/keys/:id"},
-	{"POST", "/user/keys"},
-	{"DELETE", "/user/keys/:id"},
+	tests := []struct {
+		path    string
+		wantRes MatchResult[int]
+	}{
+		{path: "/users/new", wantRes: MatchResult[int]{Value: 1000, Pattern: "/users/new"}},
+		{path: "/users/newer", wantRes: MatchResult[int]{Params: Params{{Key: "user", Value: "newer"}}, Value: values["/users/:user"], Pattern: "/users/:user"}},
+		{path: "/users/new/repos", wantRes: MatchResult[int]{Params: Params{{Key: "user", Value: "new"}}, Value: values["/users/:user/repos"], Pattern: "/users/:user/repos"}},
+		{path: "/users/new/repos/starred", wantRes: MatchResult[int]{Value: 1001, Pattern: "/users/new/repos/starred"}},
+		{path: "/gists/public", wantRes: MatchResult[int]{Value: 1002, Pattern: "/gists/public"}},
+		{path: "/gists/public/star", wantRes: MatchResult[int]{Params: Params{{Key: "id", Value: "public"}}, Value: values["/gists/:id/star"], Pattern: "/gists/:id/star"}},
+		{path: "/repos/a/b/issues/new", wantRes: MatchResult[int]{Params: Params{{Key: "owner", Value: "a"}, {Key: "repo", Value: "b"}}, Value: 1003, Pattern: "/repos/:owner/:repo/issues/new"}},
+		{path: "/repos/a/b/issues/1", wantRes: MatchResult[int]{Params: Params{{Key: "owner", Value: "a"}, {Key: "repo", Value: "b"}, {Key: "number", Value: "1"}}, Value: values["/repos/:owner/:repo/issues/:number"], Pattern: "/repos/:owner/:repo/issues/:number"}},
+		{path: "/repos/vizee/pathrouter", wantRes: MatchResult[int]{Params: Params{{Key: "repo", Value: "pathrouter"}}, Value: 1004, Pattern: "/repos/vizee/:repo"}},
+		{path: "/repos/vizee/pathrouter/events", wantRes: MatchResult[int]{Params: Params{{Key: "owner", Value: "vizee"}, {Key: "repo", Value: "pathrouter"}}, Value: values["/repos/:owner/:repo/events"], Pattern: "/repos/:owner/:repo/events"}},
+		{path: "/orgs/a/teams", wantRes: MatchResult[int]{Params: Params{{Key: "org", Value: "a"}}, Value: values["/orgs/:org/teams"], Pattern: "/orgs/:org/teams"}},
+		{path: "/orgs/a/unknown/x", wantRes: MatchResult[int]{Params: Params{{Key: "org", Value: "a"}, {Key: "rest", Value: "unknown/x"}}, Value: 1005, Pattern: "/orgs/:org/*rest"}},
+	}
+	for _, tt := range tests {
+		var res MatchResult[int]
+		if !r.Match(tt.path, &res) {
+			t.Errorf("Router.Match(%q) = false", tt.path)
+			continue
+		}
+		if !reflect.DeepEqual(res, tt.wantRes) {
+			t.Errorf("Router.Match(%q) got %+v, want %+v", tt.path, res, tt.wantRes)
+		}
+	}
 }
 
 func BenchmarkGithubRoutes(b *testing.B) {
@@ -449,6 +588,7 @@ func BenchmarkGithubRoutes(b *testing.B) {
 		}
 	}
 	res := MatchResult[int]{Params: make(Params, 0, 10)}
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,112 @@
+package pathrouter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseServeMuxPattern 将 http.ServeMux 的 pattern 转换为 Router 的 pattern
+// pattern 的格式为 [METHOD ][HOST]/[PATH]，{name} 转换为 :name，{name...} 转换为 *name，
+// 以 / 结尾的 pattern 匹配所有以其开头的路径，转换为 *，{$} 只匹配以 / 结尾的路径本身
+// Router 无法表达的 pattern 返回 ErrInvalidPath，例如包含 : 或 * 的静态文本和非 ASCII 的名称
+func ParseServeMuxPattern(pattern string) (method string, host string, path string, err error) {
+	rest := pattern
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		method, rest = rest[:i], strings.TrimLeft(rest[i+1:], " \t")
+		if !isToken(method) {
+			return "", "", "", fmt.Errorf("%w: bad method in %q", ErrInvalidPath, pattern)
+		}
+	}
+	slash := strings.IndexByte(rest, '/')
+	if slash < 0 {
+		return "", "", "", fmt.Errorf("%w: missing path in %q", ErrInvalidPath, pattern)
+	}
+	host, rest = rest[:slash], rest[slash:]
+	if strings.ContainsAny(host, "{}") {
+		return "", "", "", fmt.Errorf("%w: host contains wildcard in %q", ErrInvalidPath, pattern)
+	}
+
+	var b strings.Builder
+	names := map[string]bool{}
+	segs := strings.Split(rest[1:], "/")
+	for i, seg := range segs {
+		b.WriteByte('/')
+		last := i == len(segs)-1
+		if !strings.HasPrefix(seg, "{") {
+			if strings.ContainsAny(seg, "{}") {
+				return "", "", "", fmt.Errorf("%w: wildcard must be a full segment in %q", ErrInvalidPath, pattern)
+			}
+			lit, err := url.PathUnescape(seg)
+			if err != nil {
+				return "", "", "", fmt.Errorf("%w: %v in %q", ErrInvalidPath, err, pattern)
+			}
+			if strings.ContainsAny(lit, ":*/") {
+				return "", "", "", fmt.Errorf("%w: literal %q is not supported in %q", ErrInvalidPath, lit, pattern)
+			}
+			if lit == "" && last {
+				// 以 / 结尾时匹配所有子路径
+				lit = "*"
+			}
+			b.WriteString(lit)
+			continue
+		}
+
+		if !strings.HasSuffix(seg, "}") {
+			return "", "", "", fmt.Errorf("%w: wildcard must be a full segment in %q", ErrInvalidPath, pattern)
+		}
+		name := seg[1 : len(seg)-1]
+		if name == "$" {
+			if !last {
+				return "", "", "", fmt.Errorf("%w: {$} not at end in %q", ErrInvalidPath, pattern)
+			}
+			continue
+		}
+		multi := strings.HasSuffix(name, "...")
+		if multi {
+			if !last {
+				return "", "", "", fmt.Errorf("%w: {%s} not at end in %q", ErrInvalidPath, name, pattern)
+			}
+			name = name[:len(name)-3]
+		}
+		if !isIdent(name) {
+			return "", "", "", fmt.Errorf("%w: bad wildcard name %q in %q", ErrInvalidPath, name, pattern)
+		}
+		if names[name] {
+			return "", "", "", fmt.Errorf("%w: duplicate wildcard name %q in %q", ErrInvalidPath, name, pattern)
+		}
+		names[name] = true
+		if multi {
+			b.WriteByte('*')
+		} else {
+			b.WriteByte(':')
+		}
+		b.WriteString(name)
+	}
+	return method, host, b.String(), nil
+}
+
+func isIdent(s string) bool {
+	if s == "" || '0' <= s[0] && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`()<>@,;:\"/[]?={}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package pathrouter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseServeMuxPattern(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		wantMethod string
+		wantHost   string
+		wantPath   string
+		wantErr    error
+	}{
+		{pattern: "/", wantPath: "/*"},
+		{pattern: "/{$}", wantPath: "/"},
+		{pattern: "/a", wantPath: "/a"},
+		{pattern: "/a/", wantPath: "/a/*"},
+		{pattern: "/a/{$}", wantPath: "/a/"},
+		{pattern: "GET /users/{id}", wantMethod: "GET", wantPath: "/users/:id"},
+		{pattern: "POST\t /users/{id}/posts", wantMethod: "POST", wantPath: "/users/:id/posts"},
+		{pattern: "/static/{path...}", wantPath: "/static/*path"},
+		{pattern: "example.com/", wantHost: "example.com", wantPath: "/*"},
+		{pattern: "GET example.com/a/{b}", wantMethod: "GET", wantHost: "example.com", wantPath: "/a/:b"},
+		{pattern: "/a%20b/{c}", wantPath: "/a b/:c"},
+		{pattern: "/a//b", wantPath: "/a//b"},
+		{pattern: "", wantErr: ErrInvalidPath},
+		{pattern: "GET", wantErr: ErrInvalidPath},
+		{pattern: "G(T /a", wantErr: ErrInvalidPath},
+		{pattern: "{host}.com/", wantErr: ErrInvalidPath},
+		{pattern: "/a{b}", wantErr: ErrInvalidPath},
+		{pattern: "/{b}c", wantErr: ErrInvalidPath},
+		{pattern: "/{a...}/b", wantErr: ErrInvalidPath},
+		{pattern: "/{$}/b", wantErr: ErrInvalidPath},
+		{pattern: "/{}", wantErr: ErrInvalidPath},
+		{pattern: "/{1a}", wantErr: ErrInvalidPath},
+		{pattern: "/{名字}", wantErr: ErrInvalidPath},
+		{pattern: "/{a}/{a}", wantErr: ErrInvalidPath},
+		{pattern: "/a:b", wantErr: ErrInvalidPath},
+		{pattern: "/a*", wantErr: ErrInvalidPath},
+		{pattern: "/a%2Fb", wantErr: ErrInvalidPath},
+		{pattern: "/a%zz", wantErr: ErrInvalidPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			method, host, path, err := ParseServeMuxPattern(tt.pattern)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseServeMuxPattern() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if method != tt.wantMethod || host != tt.wantHost || path != tt.wantPath {
+				t.Errorf("ParseServeMuxPattern() got %q, %q, %q, want %q, %q, %q", method, host, path, tt.wantMethod, tt.wantHost, tt.wantPath)
+			}
+			if err == nil {
+				r := &Router[int]{}
+				if err := r.Add(path, 0); err != nil {
+					t.Errorf("Router.Add(%q) error = %v", path, err)
+				}
+			}
+		})
+	}
+}